@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juev/instapaper-collector/internal/htmltext"
+)
+
+type atomFeed struct {
+	Updated string      `xml:"http://www.w3.org/2005/Atom updated"`
+	Entries []atomEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      atomText       `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// atomText is an Atom text construct: plain text, escaped HTML or inline XHTML.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// markup returns the construct as HTML, or as is for plain text.
+// Inline XHTML is unwrapped from its <div>.
+func (t atomText) markup() string {
+	if t.Type != "xhtml" {
+		return t.Text
+	}
+
+	inner := strings.TrimSpace(t.Inner)
+	if i := strings.IndexByte(inner, '>'); strings.HasPrefix(inner, "<") && i > 0 {
+		if j := strings.LastIndex(inner, "</"); j > i {
+			inner = inner[i+1 : j]
+		}
+	}
+	return strings.TrimSpace(inner)
+}
+
+// text returns the construct as plain text.
+func (t atomText) text() string {
+	switch t.Type {
+	case "html", "xhtml":
+		return strings.Join(strings.Fields(htmltext.Text(t.markup())), " ")
+	default:
+		return t.Text
+	}
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// ParseAtom parses an Atom 1.0 document into items.
+// The entry link is taken from link[@rel="alternate"] (rel defaults to alternate),
+// the date from published, falling back to updated. HTML and XHTML titles are
+// reduced to text; XHTML summaries and content are kept as their markup.
+func ParseAtom(data []byte) ([]Item, error) {
+	return Parser{}.ParseAtom(data)
+}
+
+// ParseAtom parses an Atom 1.0 document into items. See the package-level ParseAtom.
+func (p Parser) ParseAtom(data []byte) ([]Item, error) {
+	data, err := toUTF8("", data)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse Atom: %w", err)
+	}
+
+	items := make([]Item, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		link := strings.TrimSpace(e.alternateLink())
+		if link == "" {
+			continue
+		}
+
+		title := strings.TrimSpace(e.Title.text())
+		if title == "" {
+			title = "Untitled"
+		}
+
+		date := e.Published
+		if strings.TrimSpace(date) == "" {
+			date = e.Updated
+		}
+		published, ok := p.itemDate(link, date, feed.Updated)
+		if !ok {
+			continue
+		}
+
+		description := e.Summary.markup()
+		if strings.TrimSpace(description) == "" {
+			description = e.Content.markup()
+		}
+
+		items = append(items, Item{
+			Title:       title,
+			Link:        link,
+			Description: description,
+			Published:   published,
+			GUID:        strings.TrimSpace(e.ID),
+			Author:      e.author(),
+			Categories:  e.categories(),
+			Content:     e.Content.markup(),
+			Enclosures:  e.enclosures(),
+			Comments:    strings.TrimSpace(e.link("replies")),
+		})
+	}
+
+	return items, nil
+}
+
+func (e atomEntry) alternateLink() string {
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func (e atomEntry) link(rel string) string {
+	for _, l := range e.Links {
+		if l.Rel == rel {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func (e atomEntry) author() string {
+	names := make([]string, 0, len(e.Authors))
+	for _, a := range e.Authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (e atomEntry) categories() []string {
+	var categories []string
+	for _, c := range e.Categories {
+		if v := firstNonEmpty(c.Label, c.Term); v != "" {
+			categories = append(categories, v)
+		}
+	}
+	return categories
+}
+
+func (e atomEntry) enclosures() []Enclosure {
+	var enclosures []Enclosure
+	for _, l := range e.Links {
+		if l.Rel == "enclosure" && strings.TrimSpace(l.Href) != "" {
+			length, _ := strconv.ParseInt(strings.TrimSpace(l.Length), 10, 64)
+			enclosures = append(enclosures, Enclosure{URL: strings.TrimSpace(l.Href), Type: l.Type, Length: length})
+		}
+	}
+	return enclosures
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseAtom_ValidFeed(t *testing.T) {
+	data, err := os.ReadFile("testdata/atom.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	items, err := ParseAtom(data)
+	if err != nil {
+		t.Fatalf("ParseAtom() error: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items (entry without alternate link skipped), got %d", len(items))
+	}
+
+	if items[0].Title != "Atom One" {
+		t.Errorf("items[0].Title: got %q, want %q", items[0].Title, "Atom One")
+	}
+	if items[0].Link != "https://example.org/atom-one" {
+		t.Errorf("items[0].Link: got %q, want %q", items[0].Link, "https://example.org/atom-one")
+	}
+	if items[0].Description != "First atom summary." {
+		t.Errorf("items[0].Description: got %q, want %q", items[0].Description, "First atom summary.")
+	}
+	if items[0].Published != "2025-02-28T09:00:00Z" {
+		t.Errorf("items[0].Published: got %q, want %q (published preferred, converted to UTC)", items[0].Published, "2025-02-28T09:00:00Z")
+	}
+}
+
+func TestParseAtom_Fallbacks(t *testing.T) {
+	data, err := os.ReadFile("testdata/atom.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	items, err := ParseAtom(data)
+	if err != nil {
+		t.Fatalf("ParseAtom() error: %v", err)
+	}
+
+	if items[1].Link != "https://example.org/atom-two" {
+		t.Errorf("items[1].Link: got %q, want %q (link without rel is alternate)", items[1].Link, "https://example.org/atom-two")
+	}
+	if items[1].Description != "Second atom content." {
+		t.Errorf("items[1].Description: got %q, want %q (content used when summary is empty)", items[1].Description, "Second atom content.")
+	}
+	if items[1].Published != "2025-02-27T08:30:00Z" {
+		t.Errorf("items[1].Published: got %q, want %q (updated used when published is missing)", items[1].Published, "2025-02-27T08:30:00Z")
+	}
+	if items[2].Title != "Untitled" {
+		t.Errorf("items[2].Title: got %q, want %q", items[2].Title, "Untitled")
+	}
+}
+
+func TestParseAtom_Metadata(t *testing.T) {
+	data, err := os.ReadFile("testdata/atom.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	items, err := ParseAtom(data)
+	if err != nil {
+		t.Fatalf("ParseAtom() error: %v", err)
+	}
+
+	got := items[0]
+	if got.Author != "Jane Doe, John Roe" {
+		t.Errorf("Author: got %q, want %q", got.Author, "Jane Doe, John Roe")
+	}
+	if !slices.Equal(got.Categories, []string{"go", "Web development"}) {
+		t.Errorf("Categories: got %q (label preferred over term)", got.Categories)
+	}
+	want := []Enclosure{{URL: "https://example.org/atom-one.mp4", Type: "video/mp4", Length: 2048}}
+	if !slices.Equal(got.Enclosures, want) {
+		t.Errorf("Enclosures: got %+v, want %+v", got.Enclosures, want)
+	}
+	if got.Comments != "https://example.org/atom-one/comments" {
+		t.Errorf("Comments: got %q", got.Comments)
+	}
+	if items[1].Content != "Second atom content." {
+		t.Errorf("items[1].Content: got %q", items[1].Content)
+	}
+}
+
+func TestParseAtom_TextConstructs(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<entry>
+<title type="html">&lt;b&gt;Bold&lt;/b&gt; title</title>
+<link href="https://example.org/xhtml"/>
+<updated>2025-02-28T10:00:00Z</updated>
+<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Inline &amp; <b>rich</b></p></div></content>
+</entry>
+<entry>
+<title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">An <em>XHTML</em> title</div></title>
+<link href="https://example.org/html"/>
+<updated>2025-02-28T10:00:00Z</updated>
+<summary type="html">&lt;p&gt;Escaped HTML&lt;/p&gt;</summary>
+</entry>
+</feed>`)
+
+	items, err := ParseAtom(data)
+	if err != nil {
+		t.Fatalf("ParseAtom() error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if items[0].Title != "Bold title" {
+		t.Errorf("items[0].Title: got %q, want %q", items[0].Title, "Bold title")
+	}
+	if want := "<p>Inline &amp; <b>rich</b></p>"; items[0].Description != want || items[0].Content != want {
+		t.Errorf("items[0]: got description %q, content %q, want %q", items[0].Description, items[0].Content, want)
+	}
+	if items[1].Title != "An XHTML title" {
+		t.Errorf("items[1].Title: got %q, want %q", items[1].Title, "An XHTML title")
+	}
+	if items[1].Description != "<p>Escaped HTML</p>" {
+		t.Errorf("items[1].Description: got %q", items[1].Description)
+	}
+}
+
+func TestParseFeed_DetectsFormat(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    string
+	}{
+		{"testdata/feed.xml", "https://example.com/article-one"},
+		{"testdata/atom.xml", "https://example.org/atom-one"},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.fixture)
+		if err != nil {
+			t.Fatalf("cannot read fixture: %v", err)
+		}
+
+		items, err := ParseFeed(data)
+		if err != nil {
+			t.Fatalf("ParseFeed(%s) error: %v", tt.fixture, err)
+		}
+		if len(items) == 0 || items[0].Link != tt.want {
+			t.Errorf("ParseFeed(%s): unexpected items %+v", tt.fixture, items)
+		}
+	}
+}
+
+func TestParseFeed_UnknownFormat(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?><html><body>not a feed</body></html>`)
+
+	if _, err := ParseFeed(data); err == nil {
+		t.Error("ParseFeed() should return error for unknown root element")
+	}
+}
@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// CanonicalRule rewrites a parsed link in place as one step of canonicalization.
+type CanonicalRule func(u *url.URL)
+
+// Canonicalizer maps different spellings of the same link to one key
+// for deduplication. The original link is still stored in Item.Link.
+type Canonicalizer struct {
+	Rules []CanonicalRule
+}
+
+// DefaultTrackingParams are query parameters removed by StripTrackingParams.
+// Entries ending in "*" match by prefix.
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "msclkid", "yclid", "igshid",
+	"mc_cid", "mc_eid", "_hsenc", "_hsmi", "mkt_tok", "_ga",
+}
+
+// DefaultCanonicalizer treats http/https, www., default ports, trailing slashes,
+// fragments, tracking parameters and query order as insignificant.
+func DefaultCanonicalizer() Canonicalizer {
+	return Canonicalizer{Rules: []CanonicalRule{
+		LowercaseHost,
+		UpgradeScheme,
+		StripWWW,
+		RemoveDefaultPort,
+		DropFragment,
+		StripTrackingParams(DefaultTrackingParams...),
+		TrimTrailingSlash,
+	}}
+}
+
+// Canonicalize applies the rules to link. Links that do not parse as
+// absolute URLs are returned unchanged.
+func (c Canonicalizer) Canonicalize(link string) string {
+	if len(c.Rules) == 0 {
+		return link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return link
+	}
+
+	for _, rule := range c.Rules {
+		rule(u)
+	}
+	return u.String()
+}
+
+// LowercaseHost lowercases the scheme and host.
+func LowercaseHost(u *url.URL) {
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+}
+
+// UpgradeScheme rewrites http to https.
+func UpgradeScheme(u *url.URL) {
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+}
+
+// StripWWW removes a leading "www." from the host.
+func StripWWW(u *url.URL) {
+	u.Host = strings.TrimPrefix(u.Host, "www.")
+}
+
+// RemoveDefaultPort removes :80 and :443 from the host.
+func RemoveDefaultPort(u *url.URL) {
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return
+	}
+	if port == "80" || port == "443" {
+		u.Host = host
+		if strings.Contains(host, ":") {
+			u.Host = "[" + host + "]"
+		}
+	}
+}
+
+// DropFragment removes the #fragment.
+func DropFragment(u *url.URL) {
+	u.Fragment = ""
+	u.RawFragment = ""
+}
+
+// TrimTrailingSlash removes a trailing slash from the path, including the root path.
+func TrimTrailingSlash(u *url.URL) {
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+}
+
+// StripTrackingParams returns a rule removing the named query parameters
+// and sorting the rest. Names ending in "*" match by prefix.
+func StripTrackingParams(names ...string) CanonicalRule {
+	return func(u *url.URL) {
+		if u.RawQuery == "" {
+			return
+		}
+
+		query := u.Query()
+		for key := range query {
+			if matchParam(key, names) {
+				query.Del(key)
+			}
+		}
+		// Encode sorts by key, so parameter order does not matter.
+		u.RawQuery = query.Encode()
+	}
+}
+
+// matchParam reports whether the query key matches one of names, ignoring case.
+func matchParam(key string, names []string) bool {
+	key = strings.ToLower(key)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if prefix, ok := strings.CutSuffix(name, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == name {
+			return true
+		}
+	}
+	return false
+}
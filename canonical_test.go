@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultCanonicalizer(t *testing.T) {
+	canon := DefaultCanonicalizer()
+	want := "https://example.com/article"
+
+	for _, link := range []string{
+		"https://example.com/article",
+		"http://example.com/article",
+		"https://www.example.com/article",
+		"https://EXAMPLE.com/article/",
+		"https://example.com:443/article",
+		"https://example.com/article#comments",
+		"https://example.com/article?utm_source=rss&utm_medium=feed",
+		"https://example.com/article?fbclid=abc",
+	} {
+		if got := canon.Canonicalize(link); got != want {
+			t.Errorf("Canonicalize(%q): got %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestDefaultCanonicalizer_KeepsSignificantParts(t *testing.T) {
+	canon := DefaultCanonicalizer()
+
+	tests := []struct {
+		in, want string
+	}{
+		{"https://example.com/Article?id=2&utm_source=x&a=1", "https://example.com/Article?a=1&id=2"},
+		{"https://example.com:8080/a", "https://example.com:8080/a"},
+		{"not a url", "not a url"},
+	}
+
+	for _, tt := range tests {
+		if got := canon.Canonicalize(tt.in); got != tt.want {
+			t.Errorf("Canonicalize(%q): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStripTrackingParams_IgnoresCase(t *testing.T) {
+	canon := Canonicalizer{Rules: []CanonicalRule{StripTrackingParams("UTM_*", "FbClid")}}
+
+	got := canon.Canonicalize("https://example.com/a?utm_source=x&fbclid=y&Utm_Medium=z&id=1")
+	if got != "https://example.com/a?id=1" {
+		t.Errorf("Canonicalize(): got %q, want %q", got, "https://example.com/a?id=1")
+	}
+}
+
+func TestCanonicalizer_CustomRule(t *testing.T) {
+	amp := func(u *url.URL) {
+		u.Path = strings.TrimSuffix(u.Path, "/amp")
+	}
+	canon := Canonicalizer{Rules: []CanonicalRule{amp, StripTrackingParams("ref")}}
+
+	got := canon.Canonicalize("https://example.com/story/amp?ref=home")
+	if got != "https://example.com/story" {
+		t.Errorf("Canonicalize(): got %q, want %q", got, "https://example.com/story")
+	}
+}
+
+func TestUpdate_CanonicalDeduplication(t *testing.T) {
+	feedData := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>One</title><link>https://example.com/one?utm_source=rss</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+<item><title>One again</title><link>http://www.example.com/one/#top</link><pubDate>Fri, 28 Feb 2025 11:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	c := New(path, WithFetcher(&stubFetcher{body: feedData}))
+	if _, err := c.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if len(c.Items) != 1 {
+		t.Fatalf("expected 1 item after canonical deduplication, got %d", len(c.Items))
+	}
+	if c.Items[0].Link != "https://example.com/one?utm_source=rss" {
+		t.Errorf("original link should be preserved, got %q", c.Items[0].Link)
+	}
+
+	f := &stubFetcher{body: []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>One once more</title><link>https://example.com/one</link><pubDate>Fri, 28 Feb 2025 12:00:00 GMT</pubDate></item>
+</channel></rss>`)}
+	c2 := New(path, WithFetcher(f))
+	if err := c2.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if _, err := c2.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if len(c2.Items) != 1 {
+		t.Errorf("the canonical form should match the stored link, got %d items", len(c2.Items))
+	}
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+var xmlEncodingDecl = regexp.MustCompile(`^(\s*<\?xml[^>]*?encoding\s*=\s*)(["'])([^"']*)(["'])`)
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// toUTF8 transcodes a feed document to UTF-8. The charset is taken from a
+// byte order mark, then the Content-Type header, then the XML declaration.
+// The XML declaration of a transcoded document is rewritten to say UTF-8.
+func toUTF8(contentType string, data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, utf8BOM) {
+		return rewriteXMLEncoding(data[len(utf8BOM):]), nil
+	}
+
+	label := detectCharset(contentType, data)
+	if isUTF8(label) {
+		return rewriteXMLEncoding(data), nil
+	}
+
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q: %w", label, err)
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", label, err)
+	}
+	// UTF-16 decoders keep the byte order mark as U+FEFF.
+	decoded = bytes.TrimPrefix(decoded, utf8BOM)
+
+	return rewriteXMLEncoding(decoded), nil
+}
+
+func detectCharset(contentType string, data []byte) string {
+	if bytes.HasPrefix(data, []byte{0xfe, 0xff}) {
+		return "utf-16be"
+	}
+	if bytes.HasPrefix(data, []byte{0xff, 0xfe}) {
+		return "utf-16le"
+	}
+
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return params["charset"]
+	}
+
+	if m := xmlEncodingDecl.FindSubmatch(data); m != nil {
+		return string(m[3])
+	}
+
+	return ""
+}
+
+func isUTF8(label string) bool {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return true
+	}
+	return false
+}
+
+// rewriteXMLEncoding makes the XML declaration of a UTF-8 document
+// match its encoding, so encoding/xml does not try to convert it again.
+func rewriteXMLEncoding(data []byte) []byte {
+	m := xmlEncodingDecl.FindSubmatchIndex(data)
+	if m == nil || isUTF8(string(data[m[6]:m[7]])) {
+		return data
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:m[6]]...)
+	out = append(out, "UTF-8"...)
+	out = append(out, data[m[7]:]...)
+	return out
+}
@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func encode(t *testing.T, enc *charmap.Charmap, s string) []byte {
+	t.Helper()
+	data, err := enc.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("cannot encode fixture: %v", err)
+	}
+	return data
+}
+
+func TestParseRSS_Windows1251Declaration(t *testing.T) {
+	data := encode(t, charmap.Windows1251, `<?xml version="1.0" encoding="windows-1251"?>
+<rss version="2.0"><channel>
+<item><title>Привет, мир</title><link>https://example.ru/one</link><description>Описание</description><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	items, err := ParseRSS(data)
+	if err != nil {
+		t.Fatalf("ParseRSS() error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if items[0].Title != "Привет, мир" || items[0].Description != "Описание" {
+		t.Errorf("unexpected item: %+v", items[0])
+	}
+}
+
+func TestParseFeed_UTF16WithBOM(t *testing.T) {
+	for _, endianness := range []unicode.Endianness{unicode.LittleEndian, unicode.BigEndian} {
+		data, err := unicode.UTF16(endianness, unicode.UseBOM).NewEncoder().Bytes([]byte(`<?xml version="1.0" encoding="UTF-16"?>
+<rss version="2.0"><channel>
+<item><title>Привет, мир</title><link>https://example.ru/one</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`))
+		if err != nil {
+			t.Fatalf("cannot encode fixture: %v", err)
+		}
+
+		items, err := ParseFeed(data)
+		if err != nil {
+			t.Fatalf("ParseFeed() error: %v", err)
+		}
+		if len(items) != 1 || items[0].Title != "Привет, мир" {
+			t.Errorf("unexpected items: %+v", items)
+		}
+	}
+}
+
+func TestParseFeedType_ContentTypeCharset(t *testing.T) {
+	data := encode(t, charmap.ISO8859_1, `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<entry><title>Café crème</title><link href="https://example.fr/cafe"/><updated>2025-02-28T10:00:00Z</updated></entry>
+</feed>`)
+
+	items, err := ParseFeedType("application/atom+xml; charset=ISO-8859-1", data)
+	if err != nil {
+		t.Fatalf("ParseFeedType() error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Café crème" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestParseFeedType_HeaderOverridesDeclaration(t *testing.T) {
+	data := encode(t, charmap.KOI8R, `<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0"><channel>
+<item><title>Новости</title><link>https://example.ru/news</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	items, err := ParseFeedType("text/xml; charset=koi8-r", data)
+	if err != nil {
+		t.Fatalf("ParseFeedType() error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Новости" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestParseRSS_UnknownCharset(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="x-made-up"?><rss version="2.0"><channel></channel></rss>`)
+
+	if _, err := ParseRSS(data); err == nil {
+		t.Error("ParseRSS() should return error for unknown charset")
+	}
+}
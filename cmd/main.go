@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	collector "github.com/juev/instapaper-collector"
+	"github.com/juev/instapaper-collector/exporter"
+	"github.com/juev/instapaper-collector/importer"
+	"github.com/juev/instapaper-collector/instapaper"
 	"github.com/juev/instapaper-collector/templates"
 )
 
@@ -17,39 +28,404 @@ func main() {
 }
 
 func run() error {
-	rssURL := os.Getenv("RSS_URL")
-	if rssURL == "" {
-		return fmt.Errorf("RSS_URL env variable is required")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	args := os.Args[1:]
+	if len(args) == 0 {
+		return runUpdate(ctx)
 	}
 
+	switch args[0] {
+	case "import":
+		return runImport(ctx, args[1:])
+	case "export":
+		return runExport(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// config holds the settings shared by all commands.
+type config struct {
+	dataFile  string
+	templates templates.Config
+	opts      []collector.Option
+}
+
+func loadConfig() (*config, error) {
 	userName := os.Getenv("GITHUB_USERNAME")
 	if userName == "" {
 		userName = "juev"
 	}
 
+	storeType := os.Getenv("STORE")
 	dataFile := os.Getenv("DATA_FILE")
 	if dataFile == "" {
-		dataFile = "data.json"
+		switch storeType {
+		case "sqlite":
+			dataFile = "data.db"
+		case "jsonl":
+			dataFile = "data.jsonl"
+		default:
+			dataFile = "data.json"
+		}
 	}
 
 	weekOffset := 47
 	if v := os.Getenv("WEEK_OFFSET"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil {
-			return fmt.Errorf("WEEK_OFFSET must be a number: %w", err)
+			return nil, fmt.Errorf("WEEK_OFFSET must be a number: %w", err)
 		}
 		weekOffset = n
 	}
 
-	data := collector.New(dataFile)
-	added, err := data.Update(rssURL)
+	cfg := templates.Config{UserName: userName, WeekOffset: weekOffset, BaseDir: "."}
+	switch v := os.Getenv("GROUP_BY"); v {
+	case "", "published":
+	case "collected":
+		cfg.GroupBy = templates.GroupByCollected
+	default:
+		return nil, fmt.Errorf("GROUP_BY must be published or collected, got %q", v)
+	}
+
+	cfg.SiteURL = os.Getenv("SITE_URL")
+	cfg.HTMLDir = os.Getenv("HTML_DIR")
+	cfg.Templates = os.Getenv("TEMPLATES")
+	if v := os.Getenv("TIMEZONE"); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			return nil, fmt.Errorf("TIMEZONE must be an IANA time zone: %w", err)
+		}
+		cfg.Location = loc
+	}
+	if v := os.Getenv("ARCHIVE"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("ARCHIVE must be a boolean: %w", err)
+		}
+		cfg.Archive = enabled
+	}
+	if v := os.Getenv("FEEDS"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("FEEDS must be a boolean: %w", err)
+		}
+		cfg.Feeds = enabled
+	}
+	if v := os.Getenv("RSS_FEEDS"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("RSS_FEEDS must be a boolean: %w", err)
+		}
+		cfg.RSS = enabled
+	}
+
+	var opts []collector.Option
+	if v := os.Getenv("RETRY_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("RETRY_ATTEMPTS must be a number: %w", err)
+		}
+		policy := collector.DefaultRetryPolicy
+		policy.MaxAttempts = n
+		opts = append(opts, collector.WithRetryPolicy(policy))
+	}
+
+	if v := os.Getenv("USER_AGENT"); v != "" {
+		opts = append(opts, collector.WithUserAgent(v))
+	}
+
+	switch v := os.Getenv("DATE_FALLBACK"); v {
+	case "", "now":
+	case "feed":
+		opts = append(opts, collector.WithParser(collector.Parser{DateFallback: collector.DateFromFeed}))
+	case "skip":
+		opts = append(opts, collector.WithParser(collector.Parser{DateFallback: collector.DateSkip}))
+	default:
+		return nil, fmt.Errorf("DATE_FALLBACK must be now, feed or skip, got %q", v)
+	}
+
+	if v := os.Getenv("UPDATE_EXISTING"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("UPDATE_EXISTING must be a boolean: %w", err)
+		}
+		opts = append(opts, collector.WithUpdateTracking(enabled))
+	}
+
+	if v := os.Getenv("TRACKING_PARAMS"); v != "" {
+		canon := collector.DefaultCanonicalizer()
+		canon.Rules = append(canon.Rules, collector.StripTrackingParams(strings.Split(v, ",")...))
+		opts = append(opts, collector.WithCanonicalizer(canon))
+	}
+
+	switch storeType {
+	case "", "json":
+	case "jsonl":
+		opts = append(opts, collector.WithStore(collector.NewJSONLStore(dataFile)))
+	case "sqlite":
+		store, err := collector.NewSQLiteStore(dataFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, collector.WithStore(store))
+	default:
+		return nil, fmt.Errorf("STORE must be json, jsonl or sqlite, got %q", storeType)
+	}
+
+	return &config{dataFile: dataFile, templates: cfg, opts: opts}, nil
+}
+
+// runUpdate collects new items from the feeds and renders the digests.
+func runUpdate(ctx context.Context) error {
+	feeds := parseFeeds(os.Getenv("RSS_URL"))
+	consumerKey := os.Getenv("INSTAPAPER_CONSUMER_KEY")
+	if len(feeds) == 0 && consumerKey == "" {
+		return fmt.Errorf("RSS_URL or INSTAPAPER_CONSUMER_KEY env variable is required")
+	}
+
+	conf, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	if !added {
-		return nil
+	opts := conf.opts
+	if consumerKey != "" {
+		source, err := instapaperSource(ctx, consumerKey)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, collector.WithSources(source))
+	}
+
+	data := collector.New(conf.dataFile, opts...)
+	defer func() { _ = data.Close() }()
+
+	updated, err := data.UpdateFeedsContext(ctx, feeds)
+	if err != nil && !feedErrorsOnly(err) {
+		return err
+	}
+
+	for _, item := range data.Changed() {
+		fmt.Fprintf(os.Stderr, "updated: %s\n", item.Link)
+	}
+
+	if updated {
+		if err := templates.Render(ctx, data, conf.templates); err != nil {
+			return err
+		}
 	}
 
-	return templates.TemplateFile(data, userName, weekOffset, ".")
+	// Failed feeds are reported after the others have been processed.
+	return err
+}
+
+// feedErrorsOnly reports whether err holds nothing but *collector.FeedError
+// values, so that the items of the other feeds were stored.
+func feedErrorsOnly(err error) bool {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		_, ok := err.(*collector.FeedError)
+		return ok
+	}
+	for _, err := range joined.Unwrap() {
+		if !feedErrorsOnly(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// runImport merges a bookmark export into the collection:
+//
+//	instapaper-collector import [-format instapaper|pocket|pinboard|netscape] FILE
+//
+// FILE may be "-" for standard input.
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "instapaper", "export format: "+strings.Join(importer.Formats(), ", "))
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: instapaper-collector import [-format name] FILE")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("import expects one file")
+	}
+	if !slices.Contains(importer.Formats(), *format) {
+		return fmt.Errorf("unknown import format %q, want one of %s", *format, strings.Join(importer.Formats(), ", "))
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	in := os.Stdin
+	if name := flags.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("cannot open import file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	items, err := importer.Read(*format, in)
+	if err != nil {
+		return err
+	}
+
+	data := collector.New(conf.dataFile, conf.opts...)
+	defer func() { _ = data.Close() }()
+
+	added, err := data.ImportContext(ctx, items)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "imported %d of %d items\n", added, len(items))
+
+	if added > 0 || len(data.Changed()) > 0 {
+		return templates.Render(ctx, data, conf.templates)
+	}
+	return nil
+}
+
+// runExport writes the collected items in another format:
+//
+//	instapaper-collector export [-format name] [-o FILE] [filters]
+//
+// The output goes to standard output unless -o is given.
+func runExport(ctx context.Context, args []string) error {
+	var filter exporter.Filter
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "jsonfeed", "export format: "+strings.Join(exporter.Formats(), ", "))
+	output := flags.String("o", "", "write to `file` instead of standard output")
+	from := flags.String("from", "", "only items published on or after `date`")
+	to := flags.String("to", "", "only items published before `date`")
+	flags.StringVar(&filter.Source, "source", "", "only items from `source`")
+	flags.StringVar(&filter.Category, "category", "", "only items with `category`")
+	flags.BoolVar(&filter.Starred, "starred", false, "only starred items")
+	flags.StringVar(&filter.Query, "q", "", "only items whose title, link or description contains `text`")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: instapaper-collector export [-format name] [-o file] [filters]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return errors.New("export takes no arguments")
+	}
+	if !slices.Contains(exporter.Formats(), *format) {
+		return fmt.Errorf("unknown export format %q, want one of %s", *format, strings.Join(exporter.Formats(), ", "))
+	}
+
+	var err error
+	if filter.From, err = parseDateFlag("from", *from); err != nil {
+		return err
+	}
+	if filter.To, err = parseDateFlag("to", *to); err != nil {
+		return err
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	data := collector.New(conf.dataFile, conf.opts...)
+	defer func() { _ = data.Close() }()
+
+	if err := data.ReadContext(ctx); err != nil {
+		return err
+	}
+	items := filter.Apply(data.Items)
+
+	if *output == "" {
+		return exporter.Write(*format, os.Stdout, data.Title, items)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("cannot create export file: %w", err)
+	}
+	if err := exporter.Write(*format, f, data.Title, items); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cannot write export file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "exported %d items to %s\n", len(items), *output)
+	return nil
+}
+
+// parseDateFlag parses the value of a date flag; "" yields the zero time.
+func parseDateFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := collector.ParseDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("-%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// instapaperSource configures the Instapaper API source. The access token is
+// taken from INSTAPAPER_TOKEN / INSTAPAPER_TOKEN_SECRET or, when unset,
+// requested with INSTAPAPER_USERNAME / INSTAPAPER_PASSWORD.
+func instapaperSource(ctx context.Context, consumerKey string) (*instapaper.Source, error) {
+	client := &instapaper.Client{
+		ConsumerKey:    consumerKey,
+		ConsumerSecret: os.Getenv("INSTAPAPER_CONSUMER_SECRET"),
+		Token:          os.Getenv("INSTAPAPER_TOKEN"),
+		TokenSecret:    os.Getenv("INSTAPAPER_TOKEN_SECRET"),
+	}
+
+	if client.Token == "" {
+		username := os.Getenv("INSTAPAPER_USERNAME")
+		if username == "" {
+			return nil, fmt.Errorf("INSTAPAPER_TOKEN or INSTAPAPER_USERNAME env variable is required with INSTAPAPER_CONSUMER_KEY")
+		}
+		if err := client.Login(ctx, username, os.Getenv("INSTAPAPER_PASSWORD")); err != nil {
+			return nil, err
+		}
+	}
+
+	source := &instapaper.Source{Client: client}
+	if v := os.Getenv("INSTAPAPER_FOLDERS"); v != "" {
+		source.Folders = strings.Split(v, ",")
+	}
+	return source, nil
+}
+
+// parseFeeds splits a whitespace-separated list of feed URLs.
+// Each entry may be prefixed with a source name: "pinboard=https://...".
+func parseFeeds(s string) []collector.Feed {
+	var feeds []collector.Feed
+	for _, entry := range strings.Fields(s) {
+		var feed collector.Feed
+		name, rawURL, ok := strings.Cut(entry, "=")
+		if ok && name != "" && !strings.ContainsAny(name, ":/") {
+			feed.Name, feed.URL = name, rawURL
+		} else {
+			feed.URL = entry
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds
 }
@@ -1,30 +1,37 @@
 package collector
 
 import (
-	"bytes"
 	"cmp"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"log"
-	"net/http"
-	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 )
 
-const maxResponseSize = 10 << 20 // 10MB
-
-var httpClient = &http.Client{Timeout: 30 * time.Second}
-
 type Collector struct {
-	Title    string `json:"title"`
-	Updated  string `json:"updated"`
-	Items    []Item `json:"items"`
-	fileName string
-	links    map[string]struct{}
+	Title        string               `json:"title"`
+	Updated      string               `json:"updated"`
+	Feeds        map[string]FeedState `json:"feeds,omitempty"`
+	Items        []Item               `json:"items"`
+	fileName     string
+	store        Store
+	links        map[string]string // canonical link -> Item.Link
+	guids        map[string]string // source and GUID -> Item.Link
+	stored       map[string]struct{}
+	dirty        map[string]struct{}
+	changed      []Item
+	now          func() time.Time
+	trackUpdates bool
+	canon        Canonicalizer
+	parser       Parser
+	fetcher      Fetcher
+	sources      []Source
+	http         *HTTPFetcher
+	concurrency  int
 }
 
 type Item struct {
@@ -32,123 +39,449 @@ type Item struct {
 	Link        string `json:"link,omitempty"`
 	Description string `json:"description,omitempty"`
 	Published   string `json:"published,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Author      string `json:"author,omitempty"`
+	// Categories are the item's tags as given by the feed.
+	Categories []string `json:"categories,omitempty"`
+	// Content is the full HTML content (RSS content:encoded, Atom content, JSON Feed content_html).
+	Content    string      `json:"content,omitempty"`
+	Enclosures []Enclosure `json:"enclosures,omitempty"`
+	// Comments is the URL of the item's comments page.
+	Comments string `json:"comments,omitempty"`
+	// GUID is the feed's stable identifier of the item (RSS guid, Atom id, JSON Feed id).
+	GUID string `json:"guid,omitempty"`
+	// Collected is when the collector first saw the item. Unlike Published,
+	// which some feeds set to the original article date, it is always recent.
+	Collected string `json:"collected,omitempty"`
+	// Modified is set when a stored item was updated from the feed.
+	Modified string `json:"modified,omitempty"`
+	// Starred, Progress and Highlights are the reading state
+	// reported by sources such as the Instapaper API.
+	Starred    bool        `json:"starred,omitempty"`
+	Progress   float64     `json:"progress,omitempty"`
+	Highlights []Highlight `json:"highlights,omitempty"`
+}
+
+// Highlight is a passage the reader highlighted in an item.
+type Highlight struct {
+	Text    string `json:"text"`
+	Note    string `json:"note,omitempty"`
+	Created string `json:"created,omitempty"`
 }
 
-func New(fileName string) *Collector {
-	return &Collector{
-		fileName: fileName,
-		links:    make(map[string]struct{}),
+// Enclosure is a media file attached to an item, such as a podcast episode.
+type Enclosure struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`
+	Length int64  `json:"length,omitempty"`
+}
+
+// Kind returns the media kind of the enclosure from its MIME type:
+// "audio", "video", "image" or "file".
+func (e Enclosure) Kind() string {
+	kind, _, _ := strings.Cut(e.Type, "/")
+	switch kind {
+	case "audio", "video", "image":
+		return kind
+	default:
+		return "file"
 	}
 }
 
-func (c *Collector) Read() error {
-	if _, err := os.Stat(c.fileName); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
-		}
-		return fmt.Errorf("cannot stat file %q: %w", c.fileName, err)
+// Feed is a source feed to collect items from.
+// Name is recorded as Item.Source; when empty the URL host is used,
+// so that secret feed URLs do not end up in the data file.
+type Feed struct {
+	Name string
+	URL  string
+}
+
+// FeedError reports a failure to fetch or parse a single feed.
+type FeedError struct {
+	// URL is the feed URL, or the name of a failing Source.
+	URL string
+	Err error
+}
+
+func (e *FeedError) Error() string {
+	return fmt.Sprintf("feed %s: %v", e.URL, e.Err)
+}
+
+func (e *FeedError) Unwrap() error {
+	return e.Err
+}
+
+func New(fileName string, opts ...Option) *Collector {
+	c := &Collector{
+		fileName:    fileName,
+		store:       NewJSONStore(fileName),
+		links:       make(map[string]string),
+		guids:       make(map[string]string),
+		stored:      make(map[string]struct{}),
+		dirty:       make(map[string]struct{}),
+		http:        &HTTPFetcher{},
+		concurrency: maxConcurrentFetches,
+		canon:       DefaultCanonicalizer(),
+		now:         time.Now,
 	}
+	c.fetcher = defaultFetcher{http: c.http, file: &FileFetcher{}}
 
-	data, err := os.ReadFile(c.fileName)
-	if err != nil {
-		return fmt.Errorf("cannot read file %q: %w", c.fileName, err)
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	if err := json.Unmarshal(data, c); err != nil {
-		return fmt.Errorf("invalid JSON in %q: %w", c.fileName, err)
+	return c
+}
+
+func (c *Collector) Read() error {
+	return c.ReadContext(context.Background())
+}
+
+// ReadContext loads the collection from the store into the collector.
+func (c *Collector) ReadContext(ctx context.Context) error {
+	meta, items, err := c.store.Load(ctx)
+	if err != nil {
+		return err
 	}
 
-	filtered := make([]Item, 0, len(c.Items))
-	for _, item := range c.Items {
-		if item.Link == "" {
-			log.Printf("skipping item with empty link: %q", item.Title)
-			continue
-		}
-		filtered = append(filtered, item)
-		c.links[item.Link] = struct{}{}
+	c.Title, c.Updated, c.Feeds = meta.Title, meta.Updated, meta.Feeds
+	c.Items = items
+	c.links = make(map[string]string, len(items))
+	c.guids = make(map[string]string, len(items))
+	c.stored = make(map[string]struct{}, len(items))
+	c.dirty = make(map[string]struct{})
+	c.changed = nil
+	for _, item := range items {
+		c.index(item)
+		c.stored[item.Link] = struct{}{}
 	}
-	c.Items = filtered
 
 	return nil
 }
 
 func (c *Collector) Write() error {
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	enc.SetIndent("", "    ")
-	enc.SetEscapeHTML(false)
+	return c.WriteContext(context.Background())
+}
 
-	if err := enc.Encode(c); err != nil {
+// WriteContext saves the collection after callers edited c.Items. Stores
+// implementing Replacer are rewritten from c.Items, so edits and removals are
+// kept; other stores, such as the append-only JSONLStore, get the items that
+// are not stored yet or were changed since they were read.
+func (c *Collector) WriteContext(ctx context.Context) error {
+	r, ok := c.store.(Replacer)
+	if !ok {
+		return c.appendChanges(ctx)
+	}
+
+	meta := Meta{Title: c.Title, Updated: c.Updated, Feeds: c.Feeds}
+	if err := r.Replace(ctx, meta, c.Items); err != nil {
 		return err
 	}
+	clear(c.stored)
+	clear(c.dirty)
+	for _, item := range c.Items {
+		c.stored[item.Link] = struct{}{}
+	}
+	return nil
+}
+
+// appendChanges saves the collection metadata and appends the items that are
+// not in the store yet or were changed by add since they were read. Update and
+// Import use it so that a run does not rewrite the stored items.
+func (c *Collector) appendChanges(ctx context.Context) error {
+	meta := Meta{Title: c.Title, Updated: c.Updated, Feeds: c.Feeds}
 
-	tmp := c.fileName + ".tmp"
-	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
-		return fmt.Errorf("cannot write temp file %q: %w", tmp, err)
+	var pending []Item
+	for _, item := range c.Items {
+		_, stored := c.stored[item.Link]
+		_, dirty := c.dirty[item.Link]
+		if !stored || dirty {
+			pending = append(pending, item)
+		}
 	}
 
-	if err := os.Rename(tmp, c.fileName); err != nil {
-		_ = os.Remove(tmp)
-		return fmt.Errorf("cannot rename %q to %q: %w", tmp, c.fileName, err)
+	if err := c.store.Append(ctx, meta, pending); err != nil {
+		return err
+	}
+
+	for _, item := range pending {
+		c.stored[item.Link] = struct{}{}
+		delete(c.dirty, item.Link)
 	}
 
 	return nil
 }
 
+// Store returns the store backing the collector.
+func (c *Collector) Store() Store {
+	return c.store
+}
+
+// Close releases the store if it holds resources such as a database handle.
+func (c *Collector) Close() error {
+	if closer, ok := c.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Update collects items from a single feed URL. See UpdateFeeds.
 func (c *Collector) Update(rssURL string) (bool, error) {
-	if err := c.Read(); err != nil {
-		return false, err
+	return c.UpdateContext(context.Background(), rssURL)
+}
+
+// UpdateContext is like Update but carries a context. See UpdateFeedsContext.
+func (c *Collector) UpdateContext(ctx context.Context, rssURL string) (bool, error) {
+	return c.UpdateFeedsContext(ctx, []Feed{{URL: rssURL}})
+}
+
+// UpdateFeeds is like UpdateFeedsContext with a background context.
+func (c *Collector) UpdateFeeds(feeds []Feed) (bool, error) {
+	return c.UpdateFeedsContext(context.Background(), feeds)
+}
+
+// UpdateFeedsContext fetches all feeds concurrently and adds unseen items to the
+// collection. It reports whether items were added or, with update tracking
+// enabled, modified (see Changed).
+// Sources added with WithSources are collected in the same run.
+// A failing feed does not abort the others: items from the remaining feeds are
+// still stored, and the failures are returned joined as *FeedError values.
+// If the collection cannot be written, it reports false and the returned
+// error also holds the store error, which is not a *FeedError.
+// If ctx is canceled, nothing is written and ctx.Err() is returned.
+func (c *Collector) UpdateFeedsContext(ctx context.Context, feeds []Feed) (bool, error) {
+	if len(feeds) == 0 && len(c.sources) == 0 {
+		return false, errors.New("no feeds to collect")
 	}
 
-	body, err := FetchRSS(rssURL)
-	if err != nil {
+	if err := c.ReadContext(ctx); err != nil {
 		return false, err
 	}
 
-	items, err := ParseRSS(body)
-	if err != nil {
+	states := make([]FeedState, len(feeds))
+	for i, feed := range feeds {
+		states[i] = c.Feeds[feed.key()]
+	}
+
+	results := append(c.fetchFeeds(ctx, feeds, states), c.fetchSources(ctx)...)
+	if err := ctx.Err(); err != nil {
 		return false, err
 	}
 
-	added := false
-	for _, item := range items {
-		if c.isNewLink(item.Link) {
-			c.Items = append(c.Items, item)
-			c.links[item.Link] = struct{}{}
-			added = true
+	var errs []error
+	added, modified, changed := false, false, false
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, &FeedError{URL: cmp.Or(r.feed.URL, r.feed.Name), Err: r.err})
+			continue
+		}
+
+		if r.feed.URL != "" && c.setFeedState(r.feed, r.state) {
+			changed = true
+		}
+
+		for _, item := range r.items {
+			item.Source = r.feed.source()
+			switch c.add(item) {
+			case itemAdded:
+				added = true
+			case itemModified:
+				modified = true
+			case itemBackfilled:
+				changed = true
+			}
 		}
 	}
+	feedErr := errors.Join(errs...)
 
-	if !added {
-		return false, nil
+	if !added && !modified {
+		if changed {
+			if err := c.appendChanges(ctx); err != nil {
+				return false, errors.Join(fmt.Errorf("cannot write collection: %w", err), feedErr)
+			}
+		}
+		return false, feedErr
 	}
 
 	slices.SortFunc(c.Items, func(a, b Item) int {
 		return cmp.Compare(a.Published, b.Published)
 	})
 
-	c.Updated = time.Now().UTC().Format(time.RFC3339)
+	c.Updated = c.now().UTC().Format(time.RFC3339)
 
-	return true, c.Write()
+	if err := c.appendChanges(ctx); err != nil {
+		return false, errors.Join(fmt.Errorf("cannot write collection: %w", err), feedErr)
+	}
+	return true, feedErr
 }
 
-func FetchRSS(rawURL string) ([]byte, error) {
-	resp, err := httpClient.Get(rawURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch RSS: %w", err)
+func (c *Collector) Import(items []Item) (int, error) {
+	return c.ImportContext(context.Background(), items)
+}
+
+// ImportContext adds items from an export of another service, skipping those
+// already collected. Items are matched like fetched ones: by GUID within their
+// source, then by canonical link. New items keep their Collected time when set;
+// known ones are backfilled and, with WithUpdateTracking, merged as in Update.
+// All changes are written in a single store update.
+// It returns the number of items added.
+func (c *Collector) ImportContext(ctx context.Context, items []Item) (int, error) {
+	if err := c.ReadContext(ctx); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, item := range items {
+		if c.add(item) == itemAdded {
+			added++
+		}
+	}
+	if added == 0 && len(c.dirty) == 0 {
+		return 0, nil
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("RSS feed returned status %d", resp.StatusCode)
+	slices.SortFunc(c.Items, func(a, b Item) int {
+		return cmp.Compare(a.Published, b.Published)
+	})
+
+	c.Updated = c.now().UTC().Format(time.RFC3339)
+
+	return added, c.appendChanges(ctx)
+}
+
+// setFeedState stores the cache validators of a feed and reports whether they changed.
+func (c *Collector) setFeedState(feed Feed, state FeedState) bool {
+	key := feed.key()
+	if c.Feeds[key] == state {
+		return false
+	}
+	if state == (FeedState{}) {
+		delete(c.Feeds, key)
+		return true
 	}
+	if c.Feeds == nil {
+		c.Feeds = make(map[string]FeedState)
+	}
+	c.Feeds[key] = state
+	return true
+}
 
-	return io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+type addResult int
+
+const (
+	itemKnown addResult = iota
+	itemAdded
+	itemBackfilled
+	itemModified
+)
+
+// add appends item unless it is already collected. Items are matched by GUID
+// within the same source first, then by canonical link.
+//
+// A stored item without a GUID that matches by link gets the GUID (and source,
+// if missing) backfilled, which migrates data collected before GUIDs were
+// recorded. With update tracking enabled, changed fields are merged into the
+// stored item as well.
+func (c *Collector) add(item Item) addResult {
+	link, ok := "", false
+	if item.GUID != "" {
+		link, ok = c.guids[guidKey(item.Source, item.GUID)]
+	}
+	if !ok {
+		link, ok = c.links[c.canon.Canonicalize(item.Link)]
+	}
+
+	if !ok {
+		if item.Collected == "" {
+			item.Collected = c.now().UTC().Format(time.RFC3339)
+		}
+		c.Items = append(c.Items, item)
+		c.index(item)
+		return itemAdded
+	}
+
+	i := slices.IndexFunc(c.Items, func(stored Item) bool { return stored.Link == link })
+	if i < 0 {
+		return itemKnown
+	}
+
+	result := itemKnown
+	if c.backfill(&c.Items[i], item) {
+		result = itemBackfilled
+	}
+	if c.trackUpdates && mergeChanges(&c.Items[i], item) {
+		c.Items[i].Modified = c.now().UTC().Format(time.RFC3339)
+		c.changed = append(c.changed, c.Items[i])
+		result = itemModified
+	}
+
+	if result != itemKnown {
+		c.index(c.Items[i])
+		c.dirty[link] = struct{}{}
+	}
+	return result
+}
+
+func (c *Collector) backfill(stored *Item, from Item) bool {
+	if stored.GUID != "" || from.GUID == "" {
+		return false
+	}
+
+	stored.GUID = from.GUID
+	if stored.Source == "" {
+		stored.Source = from.Source
+	}
+	return true
+}
+
+// mergeChanges copies the title, description and reading state of from into
+// stored and reports whether anything changed. The link and publish date are
+// kept, so the item stays in its week. A placeholder title never replaces a
+// real one, and a source without reading state never clears it.
+func mergeChanges(stored *Item, from Item) bool {
+	changed := false
+
+	if from.Title != stored.Title && from.Title != "Untitled" && from.Title != "" {
+		stored.Title = from.Title
+		changed = true
+	}
+	if from.Description != stored.Description && from.Description != "" {
+		stored.Description = from.Description
+		changed = true
+	}
+	if from.Starred && !stored.Starred {
+		stored.Starred = true
+		changed = true
+	}
+	if from.Progress != stored.Progress && from.Progress != 0 {
+		stored.Progress = from.Progress
+		changed = true
+	}
+	if len(from.Highlights) > 0 && !slices.Equal(from.Highlights, stored.Highlights) {
+		stored.Highlights = from.Highlights
+		changed = true
+	}
+
+	return changed
+}
+
+// Changed returns the stored items that were modified by the last Update.
+// It is only populated when update tracking is enabled with WithUpdateTracking.
+func (c *Collector) Changed() []Item {
+	return c.changed
+}
+
+func (c *Collector) index(item Item) {
+	c.links[c.canon.Canonicalize(item.Link)] = item.Link
+	if item.GUID != "" {
+		c.guids[guidKey(item.Source, item.GUID)] = item.Link
+	}
 }
 
-func (c *Collector) isNewLink(link string) bool {
-	_, ok := c.links[link]
-	return !ok
+// guidKey scopes a GUID to its source, since GUIDs such as JSON Feed ids
+// are only unique within one feed.
+func guidKey(source, guid string) string {
+	return source + "\x00" + guid
 }
 
 // AbsFileName returns the absolute path of the data file (for testing).
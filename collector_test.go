@@ -1,12 +1,15 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRead_NonexistentFile(t *testing.T) {
@@ -94,16 +97,27 @@ func TestDeduplication(t *testing.T) {
 		t.Fatalf("Write() error: %v", err)
 	}
 
-	c2 := New(path)
+	feedData := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>Existing</title><link>https://example.com/existing</link><pubDate>Wed, 01 Jan 2025 00:00:00 GMT</pubDate></item>
+<item><title>New</title><link>https://example.com/new</link><pubDate>Thu, 02 Jan 2025 00:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	c2 := New(path, WithFetcher(&stubFetcher{body: feedData}))
 	if err := c2.Read(); err != nil {
 		t.Fatalf("Read() error: %v", err)
 	}
 
-	if !c2.isNewLink("https://example.com/new") {
-		t.Error("isNewLink should return true for new link")
+	for range 2 {
+		if _, err := c2.Update("stub://feed"); err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+	}
+
+	if len(c2.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(c2.Items))
 	}
-	if c2.isNewLink("https://example.com/existing") {
-		t.Error("isNewLink should return false for existing link")
+	if c2.Items[0].Title != "Existing" || c2.Items[1].Link != "https://example.com/new" {
+		t.Errorf("unexpected items: %+v", c2.Items)
 	}
 }
 
@@ -262,3 +276,319 @@ func TestUpdate_ReturnsFalseWhenNoNewLinks(t *testing.T) {
 	}
 }
 
+
+func TestUpdateFeeds_MultipleSources(t *testing.T) {
+	rssData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+	atomData, err := os.ReadFile("testdata/atom.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(rssData)
+	}))
+	defer rssServer.Close()
+
+	atomServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(atomData)
+	}))
+	defer atomServer.Close()
+
+	brokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer brokenServer.Close()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	c := New(path)
+
+	added, err := c.UpdateFeeds([]Feed{
+		{Name: "instapaper", URL: rssServer.URL},
+		{URL: brokenServer.URL},
+		{Name: "team", URL: atomServer.URL},
+	})
+	if !added {
+		t.Error("UpdateFeeds() should return true when at least one feed added items")
+	}
+
+	var feedErr *FeedError
+	if !errors.As(err, &feedErr) {
+		t.Fatalf("expected *FeedError, got: %v", err)
+	}
+	if feedErr.URL != brokenServer.URL {
+		t.Errorf("FeedError.URL: got %q, want %q", feedErr.URL, brokenServer.URL)
+	}
+
+	if len(c.Items) != 6 {
+		t.Fatalf("expected 6 items from two working feeds, got %d", len(c.Items))
+	}
+
+	sources := make(map[string]int)
+	for _, item := range c.Items {
+		sources[item.Source]++
+	}
+	if sources["instapaper"] != 3 || sources["team"] != 3 {
+		t.Errorf("unexpected item sources: %v", sources)
+	}
+
+	c2 := New(path)
+	if err := c2.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(c2.Items) != 6 {
+		t.Errorf("expected 6 items persisted despite failing feed, got %d", len(c2.Items))
+	}
+}
+
+func TestUpdateFeeds_DefaultSourceIsHost(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(feedData)
+	}))
+	defer server.Close()
+
+	c := New(filepath.Join(t.TempDir(), "data.json"))
+	if _, err := c.UpdateFeeds([]Feed{{URL: server.URL + "/rss/123/secret"}}); err != nil {
+		t.Fatalf("UpdateFeeds() error: %v", err)
+	}
+
+	want := strings.TrimPrefix(server.URL, "http://")
+	if c.Items[0].Source != want {
+		t.Errorf("Items[0].Source: got %q, want %q", c.Items[0].Source, want)
+	}
+}
+
+func TestUpdate_DeduplicatesByGUID(t *testing.T) {
+	feed := func(link string) []byte {
+		return []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>One</title><link>` + link + `</link><guid isPermaLink="false">item-1</guid><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if _, err := New(path, WithFetcher(&stubFetcher{body: feed("https://example.com/one")})).Update("stub://feed"); err != nil {
+		t.Fatalf("first Update() error: %v", err)
+	}
+
+	c := New(path, WithFetcher(&stubFetcher{body: feed("https://redirect.example.net/?u=1")}))
+	added, err := c.Update("stub://feed")
+	if err != nil {
+		t.Fatalf("second Update() error: %v", err)
+	}
+	if added || len(c.Items) != 1 {
+		t.Errorf("rewritten link with the same guid should not be added, got %d items", len(c.Items))
+	}
+}
+
+func TestUpdate_BackfillsGUID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	data := `{
+		"title": "Test",
+		"updated": "2025-02-28T10:00:00Z",
+		"items": [
+			{"title": "Article One", "link": "https://example.com/article-one", "published": "2025-02-28T10:00:00Z"}
+		]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	if _, err := New(path, WithFetcher(&stubFetcher{body: feedData})).Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	c := New(path)
+	if err := c.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(c.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(c.Items))
+	}
+	for _, item := range c.Items {
+		if item.Link == "https://example.com/article-one" && item.GUID != "https://example.com/article-one" {
+			t.Errorf("existing item should get its GUID backfilled, got %q", item.GUID)
+		}
+	}
+}
+
+func TestUpdate_TracksChangedItems(t *testing.T) {
+	feed := func(title, description string) []byte {
+		return []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>` + title + `</title><link>https://example.com/one</link><description>` + description + `</description><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+<item><title>Two</title><link>https://example.com/two</link><pubDate>Fri, 28 Feb 2025 09:00:00 GMT</pubDate></item>
+</channel></rss>`)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if _, err := New(path, WithFetcher(&stubFetcher{body: feed("", "")})).Update("stub://feed"); err != nil {
+		t.Fatalf("first Update() error: %v", err)
+	}
+
+	untracked := New(path, WithFetcher(&stubFetcher{body: feed("Real Title", "Summary")}))
+	if added, err := untracked.Update("stub://feed"); err != nil || added {
+		t.Fatalf("Update() without tracking: added=%v, err=%v", added, err)
+	}
+
+	c := New(path, WithFetcher(&stubFetcher{body: feed("Real Title", "Summary")}), WithUpdateTracking(true))
+	updated, err := c.Update("stub://feed")
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if !updated {
+		t.Error("Update() should report modified items")
+	}
+
+	changed := c.Changed()
+	if len(changed) != 1 || changed[0].Link != "https://example.com/one" {
+		t.Fatalf("Changed(): unexpected items %+v", changed)
+	}
+
+	c2 := New(path)
+	if err := c2.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	for _, item := range c2.Items {
+		if item.Link != "https://example.com/one" {
+			continue
+		}
+		if item.Title != "Real Title" || item.Description != "Summary" {
+			t.Errorf("stored item not updated: %+v", item)
+		}
+		if item.Modified == "" {
+			t.Error("Modified should be set on the updated item")
+		}
+		if item.Published != "2025-02-28T10:00:00Z" {
+			t.Errorf("Published should be kept, got %q", item.Published)
+		}
+	}
+
+	c3 := New(path, WithFetcher(&stubFetcher{body: feed("", "Summary")}), WithUpdateTracking(true))
+	if updated, err := c3.Update("stub://feed"); err != nil || updated {
+		t.Errorf("placeholder title should not replace a real one: updated=%v, err=%v", updated, err)
+	}
+}
+
+func TestUpdate_SetsCollected(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	c := New(filepath.Join(t.TempDir(), "data.json"), WithFetcher(&stubFetcher{body: feedData}))
+	c.now = func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := c.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	for _, item := range c.Items {
+		if item.Collected != "2025-03-10T12:00:00Z" {
+			t.Errorf("%s: Collected got %q, want %q", item.Link, item.Collected, "2025-03-10T12:00:00Z")
+		}
+	}
+}
+
+type stubSource struct {
+	items []Item
+}
+
+func (s *stubSource) Name() string { return "stub" }
+
+func (s *stubSource) Items(ctx context.Context) ([]Item, error) { return s.items, nil }
+
+func TestUpdate_MergesReadingState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	item := Item{Title: "One", Link: "https://example.com/one", Published: "2025-02-28T10:00:00Z", GUID: "1"}
+
+	src := &stubSource{items: []Item{item}}
+	if added, err := New(path, WithSources(src)).UpdateFeeds(nil); err != nil || !added {
+		t.Fatalf("UpdateFeeds() from source: added=%v, err=%v", added, err)
+	}
+
+	item.Starred = true
+	item.Highlights = []Highlight{{Text: "quoted"}}
+	src.items = []Item{item}
+
+	c := New(path, WithSources(src), WithUpdateTracking(true))
+	updated, err := c.UpdateFeeds(nil)
+	if err != nil {
+		t.Fatalf("UpdateFeeds() error: %v", err)
+	}
+	if !updated || len(c.Items) != 1 {
+		t.Fatalf("expected the stored item to be modified, got updated=%v items=%+v", updated, c.Items)
+	}
+	if got := c.Items[0]; !got.Starred || len(got.Highlights) != 1 || got.Source != "stub" {
+		t.Errorf("reading state not merged: %+v", got)
+	}
+}
+
+func TestImport_SkipsCollectedLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+
+	c := New(path)
+	c.Items = []Item{{Title: "One", Link: "https://example.com/one", Published: "2025-02-28T10:00:00Z"}}
+	if err := c.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	added, err := New(path).Import([]Item{
+		{Title: "One again", Link: "http://www.example.com/one/?utm_source=csv", Published: "2019-01-01T00:00:00Z"},
+		{Title: "Two", Link: "https://example.com/two", Published: "2019-01-02T00:00:00Z", Collected: "2019-01-02T00:00:00Z"},
+	})
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("Import() added %d items, want 1", added)
+	}
+
+	c2 := New(path)
+	if err := c2.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(c2.Items) != 2 || c2.Items[0].Link != "https://example.com/two" {
+		t.Fatalf("unexpected stored items: %+v", c2.Items)
+	}
+	if c2.Items[0].Collected != "2019-01-02T00:00:00Z" {
+		t.Errorf("imported Collected should be kept, got %q", c2.Items[0].Collected)
+	}
+}
+
+func TestImport_MergesKnownItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+
+	c := New(path)
+	c.Items = []Item{{Title: "Untitled", Link: "https://example.com/one", Published: "2025-02-28T10:00:00Z"}}
+	if err := c.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	c2 := New(path, WithUpdateTracking(true))
+	added, err := c2.Import([]Item{{Title: "One", Link: "https://example.com/one", Description: "From the export.", Published: "2025-02-28T10:00:00Z"}})
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if added != 0 || len(c2.Changed()) != 1 {
+		t.Errorf("Import() added %d, changed %d items, want 0 and 1", added, len(c2.Changed()))
+	}
+
+	c3 := New(path)
+	if err := c3.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(c3.Items) != 1 || c3.Items[0].Title != "One" || c3.Items[0].Description != "From the export." {
+		t.Errorf("merged item was not written: %+v", c3.Items)
+	}
+}
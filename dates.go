@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayouts are the layouts tried by ParseDate, after a leading weekday
+// has been removed and a named time zone replaced by its offset.
+// Layouts without a zone are interpreted as UTC. Append to support more formats.
+var DateLayouts = []string{
+	time.RFC3339Nano,
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006",
+	"02-Jan-06 15:04:05 -0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04-07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"Jan 2, 2006 15:04:05 -0700",
+	"January 2, 2006",
+}
+
+// ZoneOffsets maps time zone abbreviations found in feeds to their UTC offsets.
+// The standard library only knows the offset of the local zone by name.
+var ZoneOffsets = map[string]time.Duration{
+	"UT":   0,
+	"UTC":  0,
+	"GMT":  0,
+	"Z":    0,
+	"EST":  -5 * time.Hour,
+	"EDT":  -4 * time.Hour,
+	"CST":  -6 * time.Hour,
+	"CDT":  -5 * time.Hour,
+	"MST":  -7 * time.Hour,
+	"MDT":  -6 * time.Hour,
+	"PST":  -8 * time.Hour,
+	"PDT":  -7 * time.Hour,
+	"WET":  0,
+	"WEST": 1 * time.Hour,
+	"BST":  1 * time.Hour,
+	"CET":  1 * time.Hour,
+	"CEST": 2 * time.Hour,
+	"EET":  2 * time.Hour,
+	"EEST": 3 * time.Hour,
+	"MSK":  3 * time.Hour,
+	"IST":  5*time.Hour + 30*time.Minute,
+	"JST":  9 * time.Hour,
+	"AEST": 10 * time.Hour,
+	"AEDT": 11 * time.Hour,
+}
+
+// ParseDate parses the date formats found in real-world feeds: RFC 822/1123
+// with single-digit days or hours, named time zones and missing or wrong
+// weekdays, and ISO 8601 with or without a zone.
+func ParseDate(s string) (time.Time, error) {
+	v := strings.Join(strings.Fields(s), " ")
+	if v == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	// The weekday carries no information and is often wrong or misspelled.
+	if i := strings.Index(v, ","); i > 0 && !strings.ContainsAny(v[:i], "0123456789 ") {
+		v = strings.TrimSpace(v[i+1:])
+	}
+	v = replaceZoneName(v)
+
+	for _, layout := range DateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported date format: %s", s)
+}
+
+// replaceZoneName replaces a trailing time zone abbreviation with a numeric offset.
+func replaceZoneName(v string) string {
+	i := strings.LastIndex(v, " ")
+	if i < 0 {
+		return v
+	}
+
+	offset, ok := ZoneOffsets[strings.ToUpper(v[i+1:])]
+	if !ok {
+		return v
+	}
+
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	hours := int(offset / time.Hour)
+	minutes := int((offset % time.Hour) / time.Minute)
+	return fmt.Sprintf("%s %s%02d%02d", v[:i], sign, hours, minutes)
+}
@@ -0,0 +1,142 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Fri, 28 Feb 2025 10:00:00 GMT", "2025-02-28T10:00:00Z"},
+		{"Mon, 3 Mar 2025 9:05:00 +0000", "2025-03-03T09:05:00Z"},
+		{"Tue, 28 Feb 2025 10:00:00 GMT", "2025-02-28T10:00:00Z"},
+		{"Thur, 27 Feb 2025 10:00:00 +0100", "2025-02-27T09:00:00Z"},
+		{"28 Feb 2025 10:00:00 EST", "2025-02-28T15:00:00Z"},
+		{"Fri, 28 Feb 2025 10:00:00 cest", "2025-02-28T08:00:00Z"},
+		{"Fri, 28 Feb 25 10:00 PST", "2025-02-28T18:00:00Z"},
+		{"Friday, 28-Feb-25 10:00:00 UTC", "2025-02-28T10:00:00Z"},
+		{"2025-02-28T10:00:00Z", "2025-02-28T10:00:00Z"},
+		{"2025-02-28T10:00:00.123+03:00", "2025-02-28T07:00:00Z"},
+		{"2025-02-28T10:00:00", "2025-02-28T10:00:00Z"},
+		{"2025-02-28 10:00:00", "2025-02-28T10:00:00Z"},
+		{"2025-02-28", "2025-02-28T00:00:00Z"},
+		{"  Fri,  28 Feb 2025\n10:00:00 GMT ", "2025-02-28T10:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if err != nil {
+			t.Errorf("ParseDate(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got.Format(time.RFC3339) != tt.want {
+			t.Errorf("ParseDate(%q): got %s, want %s", tt.in, got.Format(time.RFC3339), tt.want)
+		}
+	}
+}
+
+func TestParseDate_Invalid(t *testing.T) {
+	for _, in := range []string{"", "   ", "yesterday", "32 Feb 2025 10:00:00 GMT"} {
+		if _, err := ParseDate(in); err == nil {
+			t.Errorf("ParseDate(%q) should return error", in)
+		}
+	}
+}
+
+func TestParser_DateFallback(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<lastBuildDate>Sat, 01 Mar 2025 12:00:00 GMT</lastBuildDate>
+<item><title>Good</title><link>https://example.com/good</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+<item><title>Missing</title><link>https://example.com/missing</link></item>
+<item><title>Garbage</title><link>https://example.com/garbage</link><pubDate>sometime</pubDate></item>
+</channel></rss>`)
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		fallback DateFallback
+		count    int
+		want     string
+	}{
+		{DateFromNow, 3, "2025-03-10T08:00:00Z"},
+		{DateFromFeed, 3, "2025-03-01T12:00:00Z"},
+		{DateSkip, 1, ""},
+	}
+
+	for _, tt := range tests {
+		var warnings []error
+		p := Parser{
+			DateFallback: tt.fallback,
+			Warn:         func(err error) { warnings = append(warnings, err) },
+			Now:          func() time.Time { return now },
+		}
+
+		items, err := p.ParseRSS(data)
+		if err != nil {
+			t.Fatalf("fallback %d: ParseRSS() error: %v", tt.fallback, err)
+		}
+		if len(items) != tt.count {
+			t.Fatalf("fallback %d: expected %d items, got %d", tt.fallback, tt.count, len(items))
+		}
+		if len(warnings) != 2 {
+			t.Errorf("fallback %d: expected 2 warnings, got %v", tt.fallback, warnings)
+		}
+		for _, item := range items[1:] {
+			if item.Published != tt.want {
+				t.Errorf("fallback %d: %s Published got %q, want %q", tt.fallback, item.Link, item.Published, tt.want)
+			}
+		}
+	}
+}
+
+func TestUpdate_WarnsInsteadOfFailing(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>Bad Date</title><link>https://example.com/bad</link><pubDate>not a date</pubDate></item>
+</channel></rss>`)
+
+	var warned error
+	p := Parser{DateFallback: DateSkip, Warn: func(err error) { warned = errors.Join(warned, err) }}
+	c := New(t.TempDir()+"/data.json", WithFetcher(&stubFetcher{body: data}), WithParser(p))
+
+	added, err := c.Update("stub://feed")
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if added {
+		t.Error("item with bad date should be skipped")
+	}
+	if warned == nil {
+		t.Error("expected a warning for the skipped item")
+	}
+}
+
+func TestUpdateFeeds_SerializesWarn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>Bad Date</title><link>https://example.com%s</link><pubDate>not a date</pubDate></item>
+</channel></rss>`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	var warnings []error
+	p := Parser{DateFallback: DateSkip, Warn: func(err error) { warnings = append(warnings, err) }}
+	c := New(t.TempDir()+"/data.json", WithParser(p), WithConcurrency(4))
+
+	var feeds []Feed
+	for i := range 8 {
+		feeds = append(feeds, Feed{URL: fmt.Sprintf("%s/%d", server.URL, i)})
+	}
+	if _, err := c.UpdateFeeds(feeds); err != nil {
+		t.Fatalf("UpdateFeeds() error: %v", err)
+	}
+	if len(warnings) != len(feeds) {
+		t.Errorf("expected %d warnings, got %d", len(feeds), len(warnings))
+	}
+}
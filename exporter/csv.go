@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// WriteCSV writes items as CSV. The first five columns follow Instapaper's
+// export (URL, Title, Selection, Folder, Timestamp), so the file can be
+// imported back; Published, Source and Tags (comma-separated) follow.
+// Starred items are placed in the Starred folder.
+func WriteCSV(w io.Writer, _ string, items []collector.Item) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"URL", "Title", "Selection", "Folder", "Timestamp", "Published", "Source", "Tags"})
+
+	for _, item := range items {
+		folder := ""
+		if item.Starred {
+			folder = "Starred"
+		}
+
+		timestamp := ""
+		if t := itemTime(item); !t.IsZero() {
+			timestamp = strconv.FormatInt(t.Unix(), 10)
+		}
+
+		_ = cw.Write([]string{
+			item.Link,
+			item.Title,
+			item.Description,
+			folder,
+			timestamp,
+			item.Published,
+			item.Source,
+			strings.Join(item.Categories, ","),
+		})
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package exporter writes collected items in the formats of other services
+// and tools, the counterpart of package importer.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// Writer writes items in an export format. Title names the collection
+// in formats that have a document title.
+type Writer func(w io.Writer, title string, items []collector.Item) error
+
+// writers maps the format names accepted by Write to their writers.
+var writers = map[string]Writer{
+	"csv":      WriteCSV,
+	"netscape": WriteNetscapeHTML,
+	"opml":     WriteOPML,
+	"jsonfeed": WriteJSONFeed,
+	"pinboard": WritePinboardJSON,
+}
+
+// Formats returns the format names accepted by Write, sorted.
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for name := range writers {
+		formats = append(formats, name)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// Write writes items in the named format: "csv", "netscape" (bookmark HTML
+// that browsers import), "opml", "jsonfeed" or "pinboard" (JSON).
+func Write(format string, w io.Writer, title string, items []collector.Item) error {
+	write, ok := writers[format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q, want one of %s", format, strings.Join(Formats(), ", "))
+	}
+	return write(w, title, items)
+}
+
+// Filter selects the items to export. Zero fields match every item.
+type Filter struct {
+	// From and To limit Published to [From, To).
+	From, To time.Time
+	Source   string
+	// Category matches items with the category, ignoring case.
+	Category string
+	Starred  bool
+	// Query matches items whose title, link or description contains it, ignoring case.
+	Query string
+}
+
+// Apply returns the items that match the filter, in their original order.
+func (f Filter) Apply(items []collector.Item) []collector.Item {
+	var matched []collector.Item
+	for _, item := range items {
+		if f.match(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+func (f Filter) match(item collector.Item) bool {
+	if !f.From.IsZero() || !f.To.IsZero() {
+		published, err := time.Parse(time.RFC3339, item.Published)
+		if err != nil {
+			return false
+		}
+		if !f.From.IsZero() && published.Before(f.From) {
+			return false
+		}
+		if !f.To.IsZero() && !published.Before(f.To) {
+			return false
+		}
+	}
+
+	if f.Source != "" && item.Source != f.Source {
+		return false
+	}
+	if f.Category != "" && !slices.ContainsFunc(item.Categories, func(c string) bool {
+		return strings.EqualFold(c, f.Category)
+	}) {
+		return false
+	}
+	if f.Starred && !item.Starred {
+		return false
+	}
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		if !strings.Contains(strings.ToLower(item.Title), q) &&
+			!strings.Contains(strings.ToLower(item.Link), q) &&
+			!strings.Contains(strings.ToLower(item.Description), q) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// itemTime returns the time the item was saved: Collected when set, else Published.
+func itemTime(item collector.Item) time.Time {
+	for _, s := range []string{item.Collected, item.Published} {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
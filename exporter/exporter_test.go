@@ -0,0 +1,163 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+	"github.com/juev/instapaper-collector/importer"
+)
+
+var testItems = []collector.Item{
+	{
+		Title:       "Go & Rust",
+		Link:        "https://example.com/one?a=1&b=2",
+		Description: "A comparison, with \"quotes\".",
+		Published:   "2025-02-28T10:00:00Z",
+		Collected:   "2025-03-01T08:00:00Z",
+		Source:      "instapaper",
+		Categories:  []string{"go", "long reads"},
+		Starred:     true,
+	},
+	{
+		Title:     "Second",
+		Link:      "https://example.com/two",
+		Published: "2025-03-03T10:00:00Z",
+		Source:    "pinboard",
+	},
+}
+
+// TestWrite_RoundTrip writes the items and reads them back with the
+// matching importer or parser.
+func TestWrite_RoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		read   func(data []byte) ([]collector.Item, error)
+		// tags is the expected category list of the first item.
+		tags []string
+	}{
+		{"csv", func(data []byte) ([]collector.Item, error) {
+			return importer.ReadInstapaperCSV(bytes.NewReader(data))
+		}, nil},
+		{"netscape", func(data []byte) ([]collector.Item, error) {
+			return importer.ReadNetscapeHTML(bytes.NewReader(data))
+		}, []string{"go", "long reads"}},
+		{"pinboard", func(data []byte) ([]collector.Item, error) {
+			return importer.ReadPinboardJSON(bytes.NewReader(data))
+		}, []string{"go", "long_reads"}},
+		{"jsonfeed", collector.ParseJSONFeed, []string{"go", "long reads"}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Write(tt.format, &buf, "Links", testItems); err != nil {
+			t.Fatalf("Write(%s) error: %v", tt.format, err)
+		}
+
+		items, err := tt.read(buf.Bytes())
+		if err != nil {
+			t.Fatalf("%s: cannot read export back: %v\n%s", tt.format, err, buf.String())
+		}
+		if len(items) != 2 {
+			t.Fatalf("%s: expected 2 items, got %d", tt.format, len(items))
+		}
+
+		got := items[0]
+		if got.Link != testItems[0].Link || got.Title != testItems[0].Title {
+			t.Errorf("%s: link or title changed: %+v", tt.format, got)
+		}
+		if tt.format != "jsonfeed" && got.Published != testItems[0].Collected {
+			t.Errorf("%s: Published got %q, want the collected time %q", tt.format, got.Published, testItems[0].Collected)
+		}
+		if tt.format != "csv" && got.Description != testItems[0].Description {
+			t.Errorf("%s: Description got %q", tt.format, got.Description)
+		}
+		if tt.tags != nil && !slices.Equal(got.Categories, tt.tags) {
+			t.Errorf("%s: Categories got %q, want %q", tt.format, got.Categories, tt.tags)
+		}
+	}
+}
+
+func TestWriteCSV_StarredFolder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, "", testItems); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+
+	items, err := importer.ReadInstapaperCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadInstapaperCSV() error: %v", err)
+	}
+	if !items[0].Starred || items[1].Starred {
+		t.Errorf("starred state not kept: %+v", items)
+	}
+}
+
+func TestWriteOPML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOPML(&buf, "Links", testItems); err != nil {
+		t.Fatalf("WriteOPML() error: %v", err)
+	}
+
+	var doc opml
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid OPML: %v\n%s", err, buf.String())
+	}
+	if doc.Title != "Links" || len(doc.Items) != 2 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+
+	want := opmlOutline{
+		Text:     "Go & Rust",
+		Type:     "link",
+		URL:      "https://example.com/one?a=1&b=2",
+		Created:  "Sat, 01 Mar 2025 08:00:00 +0000",
+		Category: "/go,/long reads",
+	}
+	if doc.Items[0] != want {
+		t.Errorf("outline: got %+v, want %+v", doc.Items[0], want)
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	err := Write("rss", &bytes.Buffer{}, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "csv, jsonfeed, netscape, opml, pinboard") {
+		t.Errorf("Write() error = %v, want the supported formats listed", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	date := func(s string) time.Time {
+		t, _ := time.Parse(time.DateOnly, s)
+		return t
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"zero", Filter{}, []string{"https://example.com/one?a=1&b=2", "https://example.com/two"}},
+		{"from", Filter{From: date("2025-03-01")}, []string{"https://example.com/two"}},
+		{"to is exclusive", Filter{To: date("2025-03-03")}, []string{"https://example.com/one?a=1&b=2"}},
+		{"source", Filter{Source: "pinboard"}, []string{"https://example.com/two"}},
+		{"category", Filter{Category: "Long Reads"}, []string{"https://example.com/one?a=1&b=2"}},
+		{"starred", Filter{Starred: true}, []string{"https://example.com/one?a=1&b=2"}},
+		{"query", Filter{Query: "SECOND"}, []string{"https://example.com/two"}},
+		{"no match", Filter{Source: "pinboard", Starred: true}, nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, item := range tt.filter.Apply(testItems) {
+			got = append(got, item.Link)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
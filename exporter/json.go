@@ -0,0 +1,129 @@
+package exporter
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title,omitempty"`
+	Summary       string               `json:"summary,omitempty"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	ContentText   string               `json:"content_text,omitempty"`
+	DatePublished string               `json:"date_published,omitempty"`
+	DateModified  string               `json:"date_modified,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MIMEType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+// WriteJSONFeed writes items as a JSON Feed 1.1 document, using the link as
+// the item id and "Links" as the title when title is empty. Items without content get the description as content_text,
+// since JSON Feed requires one of content_html and content_text.
+func WriteJSONFeed(w io.Writer, title string, items []collector.Item) error {
+	feed := jsonFeed{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title:   cmp.Or(title, "Links"),
+		Items:   make([]jsonFeedItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		ji := jsonFeedItem{
+			ID:            item.Link,
+			URL:           item.Link,
+			Title:         item.Title,
+			Summary:       item.Description,
+			ContentHTML:   item.Content,
+			DatePublished: item.Published,
+			DateModified:  item.Modified,
+			Tags:          item.Categories,
+		}
+		if ji.ContentHTML == "" {
+			ji.ContentText = item.Description
+		}
+		if item.Author != "" {
+			ji.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		for _, e := range item.Enclosures {
+			ji.Attachments = append(ji.Attachments, jsonFeedAttachment{URL: e.URL, MIMEType: e.Type, SizeInBytes: e.Length})
+		}
+		feed.Items = append(feed.Items, ji)
+	}
+
+	return writeJSON(w, feed, "JSON Feed")
+}
+
+// pinboardPost mirrors a bookmark of Pinboard's JSON export.
+type pinboardPost struct {
+	Href        string `json:"href"`
+	Description string `json:"description"`
+	Extended    string `json:"extended"`
+	Meta        string `json:"meta"`
+	Hash        string `json:"hash"`
+	Time        string `json:"time"`
+	Shared      string `json:"shared"`
+	ToRead      string `json:"toread"`
+	Tags        string `json:"tags"`
+}
+
+// WritePinboardJSON writes items in the format of Pinboard's JSON export.
+// Pinboard tags cannot contain spaces, so spaces in categories become
+// underscores. Bookmarks are marked private.
+func WritePinboardJSON(w io.Writer, _ string, items []collector.Item) error {
+	posts := make([]pinboardPost, 0, len(items))
+	for _, item := range items {
+		tags := make([]string, len(item.Categories))
+		for i, c := range item.Categories {
+			tags[i] = strings.Join(strings.Fields(c), "_")
+		}
+
+		post := pinboardPost{
+			Href:        item.Link,
+			Description: item.Title,
+			Extended:    item.Description,
+			Shared:      "no",
+			ToRead:      "no",
+			Tags:        strings.Join(tags, " "),
+		}
+		if t := itemTime(item); !t.IsZero() {
+			post.Time = t.UTC().Format(time.RFC3339)
+		}
+		posts = append(posts, post)
+	}
+
+	return writeJSON(w, posts, "Pinboard JSON")
+}
+
+func writeJSON(w io.Writer, v any, format string) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", format, err)
+	}
+	return nil
+}
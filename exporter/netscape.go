@@ -0,0 +1,66 @@
+package exporter
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+const netscapeHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+`
+
+// WriteNetscapeHTML writes items as a Netscape bookmark file, which browsers
+// and bookmarking services import. Categories are written as TAGS and the
+// description as the <DD> following the link.
+func WriteNetscapeHTML(w io.Writer, title string, items []collector.Item) error {
+	if title == "" {
+		title = "Bookmarks"
+	}
+
+	bw := bufio.NewWriter(w)
+	ew := &errWriter{w: bw}
+	ew.printf("%s", netscapeHeader)
+	ew.printf("<TITLE>%s</TITLE>\n<H1>%s</H1>\n<DL><p>\n", html.EscapeString(title), html.EscapeString(title))
+
+	for _, item := range items {
+		ew.printf(`    <DT><A HREF="%s"`, html.EscapeString(item.Link))
+		if t := itemTime(item); !t.IsZero() {
+			ew.printf(` ADD_DATE="%d"`, t.Unix())
+		}
+		if len(item.Categories) > 0 {
+			ew.printf(` TAGS="%s"`, html.EscapeString(strings.Join(item.Categories, ",")))
+		}
+		ew.printf(">%s</A>\n", html.EscapeString(item.Title))
+
+		if description := strings.TrimSpace(item.Description); description != "" {
+			ew.printf("    <DD>%s\n", html.EscapeString(description))
+		}
+	}
+
+	ew.printf("</DL><p>\n")
+	if err := cmp.Or(ew.err, bw.Flush()); err != nil {
+		return fmt.Errorf("failed to write bookmark HTML: %w", err)
+	}
+	return nil
+}
+
+// errWriter keeps the first write error, so that a run of writes is checked once.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
@@ -0,0 +1,65 @@
+package exporter
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+type opml struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title"`
+	Created string        `xml:"head>dateCreated"`
+	Items   []opmlOutline `xml:"body>outline"`
+}
+
+type opmlOutline struct {
+	Text     string `xml:"text,attr"`
+	Type     string `xml:"type,attr"`
+	URL      string `xml:"url,attr"`
+	Created  string `xml:"created,attr,omitempty"`
+	Category string `xml:"category,attr,omitempty"`
+}
+
+// WriteOPML writes items as an OPML 2.0 outline of link nodes.
+// Categories are written as the category attribute ("/go,/web").
+func WriteOPML(w io.Writer, title string, items []collector.Item) error {
+	doc := opml{
+		Version: "2.0",
+		Title:   cmp.Or(title, "Links"),
+		Created: time.Now().UTC().Format(time.RFC1123Z),
+		Items:   make([]opmlOutline, 0, len(items)),
+	}
+
+	for _, item := range items {
+		outline := opmlOutline{Text: item.Title, Type: "link", URL: item.Link}
+		if t := itemTime(item); !t.IsZero() {
+			outline.Created = t.UTC().Format(time.RFC1123Z)
+		}
+		if len(item.Categories) > 0 {
+			categories := make([]string, len(item.Categories))
+			for i, c := range item.Categories {
+				categories[i] = "/" + c
+			}
+			outline.Category = strings.Join(categories, ",")
+		}
+		doc.Items = append(doc.Items, outline)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write OPML: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write OPML: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
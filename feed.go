@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"time"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// DateFallback decides what happens to an item whose date is missing or cannot be parsed.
+type DateFallback int
+
+const (
+	// DateFromNow dates the item with the current time.
+	DateFromNow DateFallback = iota
+	// DateFromFeed uses the feed-level date (RSS lastBuildDate or pubDate,
+	// Atom updated), or the current time if the feed has none.
+	DateFromFeed
+	// DateSkip drops the item.
+	DateSkip
+)
+
+// Parser converts feed documents into items. The zero value is ready to use:
+// it dates items with bad dates by the current time and logs a warning for each.
+type Parser struct {
+	DateFallback DateFallback
+	// Warn receives per-item problems that did not stop parsing.
+	// Warnings are logged when nil. A Parser used from several goroutines
+	// calls Warn concurrently; a Collector serializes the calls.
+	Warn func(error)
+	// Now returns the current time; time.Now when nil.
+	Now func() time.Time
+}
+
+// ParseFeed detects the format of a feed document and parses it
+// with ParseRSS, ParseAtom or ParseJSONFeed.
+func ParseFeed(data []byte) ([]Item, error) {
+	return Parser{}.ParseFeed(data)
+}
+
+// ParseFeed detects the format of a feed document and parses it. See the package-level ParseFeed.
+func (p Parser) ParseFeed(data []byte) ([]Item, error) {
+	data, err := toUTF8("", data)
+	if err != nil {
+		return nil, err
+	}
+
+	if isJSON(data) {
+		return p.ParseJSONFeed(data)
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case root.Local == "rss", root.Local == "RDF":
+		return p.ParseRSS(data)
+	case root.Local == "feed" && root.Space == atomNamespace:
+		return p.ParseAtom(data)
+	default:
+		return nil, fmt.Errorf("unsupported feed format: root element <%s>", root.Local)
+	}
+}
+
+// ParseFeedType parses a feed document using its Content-Type to pick the parser.
+// Only application/feed+json is trusted; XML feeds, and documents of other
+// types, are detected by ParseFeed, since servers often serve Atom as
+// application/rss+xml and the other way around.
+func ParseFeedType(contentType string, data []byte) ([]Item, error) {
+	return Parser{}.ParseFeedType(contentType, data)
+}
+
+// ParseFeedType parses a feed document using its Content-Type to pick the parser.
+// See the package-level ParseFeedType.
+// A charset in contentType takes precedence over the XML declaration.
+func (p Parser) ParseFeedType(contentType string, data []byte) ([]Item, error) {
+	data, err := toUTF8(contentType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/feed+json" {
+		return p.ParseJSONFeed(data)
+	}
+	return p.ParseFeed(data)
+}
+
+// ParseFeedContext is like ParseFeedType but returns ctx.Err()
+// without parsing if ctx is already done.
+func ParseFeedContext(ctx context.Context, contentType string, data []byte) ([]Item, error) {
+	return Parser{}.ParseFeedContext(ctx, contentType, data)
+}
+
+// ParseFeedContext is like ParseFeedType but returns ctx.Err()
+// without parsing if ctx is already done.
+func (p Parser) ParseFeedContext(ctx context.Context, contentType string, data []byte) ([]Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.ParseFeedType(contentType, data)
+}
+
+// itemDate converts an item date to RFC 3339, applying the fallback policy
+// when it is missing or unparseable. ok is false when the item is to be skipped.
+func (p Parser) itemDate(link, date, feedDate string) (published string, ok bool) {
+	t, err := ParseDate(date)
+	if err == nil {
+		return t.Format(time.RFC3339), true
+	}
+
+	switch p.DateFallback {
+	case DateSkip:
+		p.warn(fmt.Errorf("skipping item %s: %w", link, err))
+		return "", false
+	case DateFromFeed:
+		if ft, ferr := ParseDate(feedDate); ferr == nil {
+			p.warn(fmt.Errorf("item %s: %w, using feed date", link, err))
+			return ft.Format(time.RFC3339), true
+		}
+	}
+
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	p.warn(fmt.Errorf("item %s: %w, using current time", link, err))
+	return now().UTC().Format(time.RFC3339), true
+}
+
+func (p Parser) warn(err error) {
+	if p.Warn != nil {
+		p.Warn(err)
+		return
+	}
+	log.Printf("warning: %v", err)
+}
+
+func isJSON(data []byte) bool {
+	trimmed := bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	trimmed = bytes.TrimLeft(trimmed, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// rootElement returns the name of the first element in an XML document.
+func rootElement(data []byte) (xml.Name, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return xml.Name{}, errors.New("failed to detect feed format: no root element")
+			}
+			return xml.Name{}, fmt.Errorf("failed to detect feed format: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
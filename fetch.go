@@ -0,0 +1,287 @@
+package collector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	maxResponseSize      = 10 << 20 // 10MB
+	maxConcurrentFetches = 4
+)
+
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Fetcher retrieves feed documents for Collector.Update.
+type Fetcher interface {
+	Fetch(ctx context.Context, req FetchRequest) (*FetchResponse, error)
+}
+
+// FetchRequest describes a feed to fetch.
+type FetchRequest struct {
+	URL string
+	// State holds the cache validators from the previous fetch, if any.
+	State FeedState
+}
+
+// FetchResponse is a fetched feed document.
+type FetchResponse struct {
+	Body        []byte
+	ContentType string
+	// State holds the cache validators to send on the next fetch.
+	State FeedState
+	// NotModified reports that the feed is unchanged since FetchRequest.State;
+	// Body is empty in that case.
+	NotModified bool
+}
+
+// FeedState holds the HTTP cache validators of a feed from the previous run.
+// They are sent as If-None-Match / If-Modified-Since on the next request.
+type FeedState struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// HTTPFetcher fetches feeds over HTTP with conditional requests and retries.
+// The zero value uses a client with a 30 second timeout and DefaultRetryPolicy.
+type HTTPFetcher struct {
+	Client    *http.Client
+	UserAgent string
+	// Retry overrides DefaultRetryPolicy when set.
+	Retry *RetryPolicy
+}
+
+// FileFetcher reads feeds from file:// URLs, and from Stdin for the URL "-".
+// The file modification time is used as the Last-Modified validator.
+type FileFetcher struct {
+	// Stdin is read for the URL "-"; os.Stdin when nil.
+	Stdin io.Reader
+}
+
+// defaultFetcher dispatches http(s) URLs to an HTTPFetcher and
+// file:// URLs and "-" to a FileFetcher.
+type defaultFetcher struct {
+	http *HTTPFetcher
+	file *FileFetcher
+}
+
+type feedResult struct {
+	feed  Feed
+	items []Item
+	state FeedState
+	err   error
+}
+
+func FetchRSS(rawURL string) ([]byte, error) {
+	return FetchRSSContext(context.Background(), rawURL)
+}
+
+// FetchRSSContext downloads a feed; ctx bounds the request and any retries.
+func FetchRSSContext(ctx context.Context, rawURL string) ([]byte, error) {
+	f := defaultFetcher{http: &HTTPFetcher{}, file: &FileFetcher{}}
+	resp, err := f.Fetch(ctx, FetchRequest{URL: rawURL})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// fetchFeeds fetches and parses feeds with at most c.concurrency
+// requests in flight. Results are returned in the order of feeds.
+// A feed that is not modified since states[i] yields no items.
+func (c *Collector) fetchFeeds(ctx context.Context, feeds []Feed, states []FeedState) []feedResult {
+	results := make([]feedResult, len(feeds))
+	sem := make(chan struct{}, max(c.concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, feed := range feeds {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = feedResult{feed: feed, state: states[i]}
+			resp, err := c.fetcher.Fetch(ctx, FetchRequest{URL: feed.URL, State: states[i]})
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			if resp.NotModified {
+				return
+			}
+			results[i].state = resp.State
+			results[i].items, results[i].err = c.parser.ParseFeedContext(ctx, resp.ContentType, resp.Body)
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (f defaultFetcher) Fetch(ctx context.Context, req FetchRequest) (*FetchResponse, error) {
+	if req.URL == "-" {
+		return f.file.Fetch(ctx, req)
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return f.http.Fetch(ctx, req)
+	case "file":
+		return f.file.Fetch(ctx, req)
+	default:
+		return nil, fmt.Errorf("unsupported feed URL scheme %q", u.Scheme)
+	}
+}
+
+// Fetch downloads a feed, sending the cache validators from req.State.
+// A 304 Not Modified response is reported with NotModified set.
+// Transient failures are retried according to the retry policy.
+func (f *HTTPFetcher) Fetch(ctx context.Context, req FetchRequest) (*FetchResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch RSS: %w", err)
+	}
+	if f.UserAgent != "" {
+		httpReq.Header.Set("User-Agent", f.UserAgent)
+	}
+	if req.State.ETag != "" {
+		httpReq.Header.Set("If-None-Match", req.State.ETag)
+	}
+	if req.State.LastModified != "" {
+		httpReq.Header.Set("If-Modified-Since", req.State.LastModified)
+	}
+
+	policy := DefaultRetryPolicy
+	if f.Retry != nil {
+		policy = *f.Retry
+	}
+
+	var resp *FetchResponse
+	err = policy.do(ctx, func() error {
+		var err error
+		resp, err = f.fetchOnce(httpReq, req.State)
+		return err
+	})
+	return resp, err
+}
+
+func (f *HTTPFetcher) fetchOnce(req *http.Request, state FeedState) (*FetchResponse, error) {
+	client := f.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch RSS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &FetchResponse{State: state, NotModified: true}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{
+			code:       resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RSS: %w", err)
+	}
+
+	return &FetchResponse{
+		Body:        body,
+		ContentType: resp.Header.Get("Content-Type"),
+		State: FeedState{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}, nil
+}
+
+// Fetch reads a feed from a file:// URL or, for "-", from standard input.
+func (f *FileFetcher) Fetch(ctx context.Context, req FetchRequest) (*FetchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if req.URL == "-" {
+		stdin := f.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		body, err := io.ReadAll(io.LimitReader(stdin, maxResponseSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feed from stdin: %w", err)
+		}
+		return &FetchResponse{Body: body}, nil
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed URL: %w", err)
+	}
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported feed URL scheme %q", u.Scheme)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, errors.New("file URLs with a remote host are not supported")
+	}
+
+	info, err := os.Stat(u.Path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat feed file: %w", err)
+	}
+
+	state := FeedState{LastModified: info.ModTime().UTC().Format(http.TimeFormat)}
+	if req.State.LastModified == state.LastModified {
+		return &FetchResponse{State: state, NotModified: true}, nil
+	}
+
+	file, err := os.Open(u.Path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open feed file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(file, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read feed file: %w", err)
+	}
+
+	return &FetchResponse{Body: body, State: state}, nil
+}
+
+func (f Feed) source() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if u, err := url.Parse(f.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return ""
+}
+
+// key identifies the feed in Collector.Feeds. The URL is hashed because
+// feed URLs such as Instapaper's carry a secret token.
+func (f Feed) key() string {
+	sum := sha256.Sum256([]byte(f.URL))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdate_ConditionalGet(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	const etag = `"v1"`
+	const lastModified = "Fri, 28 Feb 2025 10:00:00 GMT"
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write(feedData)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if _, err := New(path).Update(server.URL); err != nil {
+		t.Fatalf("first Update() error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if strings.Contains(string(data), server.URL) {
+		t.Error("data file should not contain the feed URL")
+	}
+
+	c := New(path)
+	added, err := c.Update(server.URL)
+	if err != nil {
+		t.Fatalf("second Update() error: %v (304 should not be an error)", err)
+	}
+	if added {
+		t.Error("second Update() should return false for 304 Not Modified")
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+	if len(c.Items) != 3 {
+		t.Errorf("expected 3 stored items, got %d", len(c.Items))
+	}
+}
+
+func TestUpdate_StoresValidatorsWithoutNewItems(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write(feedData)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	if _, err := New(path).Update(server.URL); err != nil {
+		t.Fatalf("first Update() error: %v", err)
+	}
+
+	etag = `"v2"`
+	if _, err := New(path).Update(server.URL); err != nil {
+		t.Fatalf("second Update() error: %v", err)
+	}
+
+	c := New(path)
+	if err := c.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	state := c.Feeds[Feed{URL: server.URL}.key()]
+	if state.ETag != `"v2"` {
+		t.Errorf("stored ETag: got %q, want %q", state.ETag, `"v2"`)
+	}
+}
+
+func TestUpdateContext_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	added, err := New(path).UpdateContext(ctx, server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if added {
+		t.Error("UpdateContext() should not report added items when canceled")
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Error("data file should not be written when canceled")
+	}
+}
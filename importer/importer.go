@@ -0,0 +1,137 @@
+// Package importer reads bookmark exports of other services into
+// collector items, for backfilling a collection with older saves.
+// The items are meant for Collector.Import, which deduplicates them
+// against the collection the same way Update does.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// Reader parses an export file into items.
+type Reader func(r io.Reader) ([]collector.Item, error)
+
+// readers maps the format names accepted by Read to their readers.
+var readers = map[string]Reader{
+	"instapaper": ReadInstapaperCSV,
+	"pocket":     ReadPocket,
+	"pinboard":   ReadPinboardJSON,
+	"netscape":   ReadNetscapeHTML,
+}
+
+// Formats returns the format names accepted by Read, sorted.
+func Formats() []string {
+	formats := make([]string, 0, len(readers))
+	for name := range readers {
+		formats = append(formats, name)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// Read parses an export in the named format: "instapaper" (CSV), "pocket"
+// (HTML or CSV), "pinboard" (JSON) or "netscape" (bookmark HTML as written
+// by browsers and most bookmarking services).
+func Read(format string, r io.Reader) ([]collector.Item, error) {
+	read, ok := readers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q, want one of %s", format, strings.Join(Formats(), ", "))
+	}
+	return read(r)
+}
+
+// csvRow is a CSV record whose fields are looked up by header name.
+type csvRow struct {
+	columns map[string]int
+	record  []string
+	line    int
+}
+
+// get returns the trimmed field of the named column, or "" when missing.
+// Names are matched case-insensitively.
+func (r csvRow) get(name string) string {
+	if i, ok := r.columns[name]; ok && i < len(r.record) {
+		return strings.TrimSpace(r.record[i])
+	}
+	return ""
+}
+
+// readCSV calls fn for every record of a CSV file with a header row.
+// It fails if the header has no column named required, in any case.
+func readCSV(r io.Reader, required string, fn func(row csvRow) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns[strings.ToLower(required)]; !ok {
+		return fmt.Errorf("missing %s column", required)
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := cr.FieldPos(0)
+		if err := fn(csvRow{columns: columns, record: record, line: line}); err != nil {
+			return err
+		}
+	}
+}
+
+// unixDate converts a unix timestamp in seconds to RFC 3339 in UTC.
+// An empty timestamp yields the current time.
+func unixDate(s string) (string, error) {
+	t := time.Now()
+	if s = strings.TrimSpace(s); s != "" {
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid timestamp %q", s)
+		}
+		t = time.Unix(sec, 0)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// splitTags splits a tag list on sep, dropping blank and duplicate tags.
+func splitTags(s string, sep string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, sep) {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func titleOrUntitled(title string) string {
+	if title = strings.TrimSpace(title); title != "" {
+		return title
+	}
+	return "Untitled"
+}
@@ -0,0 +1,55 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// ReadInstapaperCSV reads the CSV export from Instapaper's settings page,
+// with the columns URL, Title, Selection, Folder and Timestamp (unix seconds).
+// Columns are matched by header name, so their order does not matter.
+//
+// Selection becomes the description, and Timestamp both the publish and the
+// collected time. Items in the Starred folder are starred; custom folders are
+// recorded as categories.
+func ReadInstapaperCSV(r io.Reader) ([]collector.Item, error) {
+	var items []collector.Item
+	err := readCSV(r, "URL", func(row csvRow) error {
+		link := row.get("url")
+		if link == "" {
+			return nil
+		}
+
+		date, err := unixDate(row.get("timestamp"))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", row.line, err)
+		}
+
+		item := collector.Item{
+			Title:       titleOrUntitled(row.get("title")),
+			Link:        link,
+			Description: row.get("selection"),
+			Published:   date,
+			Collected:   date,
+			Source:      "instapaper",
+		}
+		switch folder := row.get("folder"); strings.ToLower(folder) {
+		case "", "unread", "archive":
+		case "starred":
+			item.Starred = true
+		default:
+			item.Categories = []string{folder}
+		}
+
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read Instapaper CSV: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadInstapaperCSV(t *testing.T) {
+	f, err := os.Open("testdata/instapaper-export.csv")
+	if err != nil {
+		t.Fatalf("cannot open fixture: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	items, err := ReadInstapaperCSV(f)
+	if err != nil {
+		t.Fatalf("ReadInstapaperCSV() error: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items (row without URL skipped), got %d", len(items))
+	}
+
+	if items[0].Link != "https://example.com/one" || items[0].Title != "Article One" {
+		t.Errorf("items[0]: unexpected %+v", items[0])
+	}
+	if items[0].Published != "2025-02-28T10:00:00Z" || items[0].Collected != items[0].Published {
+		t.Errorf("items[0]: timestamp not converted: %+v", items[0])
+	}
+	if items[0].Source != "instapaper" {
+		t.Errorf("items[0].Source: got %q, want %q", items[0].Source, "instapaper")
+	}
+
+	if items[1].Description != "A quoted, multi-line\nselection" {
+		t.Errorf("items[1].Description: got %q", items[1].Description)
+	}
+	if !items[1].Starred {
+		t.Error("items[1] in the Starred folder should be starred")
+	}
+
+	if items[2].Title != "Untitled" {
+		t.Errorf("items[2].Title: got %q, want %q", items[2].Title, "Untitled")
+	}
+	if len(items[2].Categories) != 1 || items[2].Categories[0] != "Reading List" {
+		t.Errorf("items[2].Categories: got %q, want the folder name", items[2].Categories)
+	}
+}
+
+func TestReadInstapaperCSV_ReorderedColumns(t *testing.T) {
+	data := "\ufeffTimestamp,Folder,URL,Title\n1740736800,Archive,https://example.com/one,One\n"
+
+	items, err := ReadInstapaperCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadInstapaperCSV() error: %v", err)
+	}
+	if len(items) != 1 || items[0].Link != "https://example.com/one" || items[0].Published != "2025-02-28T10:00:00Z" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestReadInstapaperCSV_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing URL column": "Title,Timestamp\nOne,1740736800\n",
+		"line 3":             "URL,Timestamp\nhttps://example.com/one,1740736800\nhttps://example.com/two,yesterday\n",
+	}
+
+	for want, data := range tests {
+		_, err := ReadInstapaperCSV(strings.NewReader(data))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ReadInstapaperCSV() error = %v, want it to mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// ReadNetscapeHTML reads a Netscape bookmark file, the HTML format browsers
+// and most bookmarking services export:
+//
+//	<DT><A HREF="https://..." ADD_DATE="1740736800" TAGS="go,web">Title</A>
+//	<DD>Description
+//
+// Only http(s) links are imported; folders are not recorded.
+func ReadNetscapeHTML(r io.Reader) ([]collector.Item, error) {
+	items, err := readBookmarkHTML(r, "bookmarks")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read bookmark HTML: %w", err)
+	}
+	return items, nil
+}
+
+// readBookmarkHTML collects the links of a Netscape-style bookmark file.
+// The date is taken from ADD_DATE or, as written by Pocket, TIME_ADDED.
+func readBookmarkHTML(r io.Reader, source string) ([]collector.Item, error) {
+	var (
+		items         []collector.Item
+		current       *collector.Item
+		title         strings.Builder
+		inDescription bool
+		// afterItem is set between the </A> of the last item and the next
+		// tag, the only place its <DD> description may start.
+		afterItem bool
+	)
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return items, nil
+			}
+			return nil, z.Err()
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			inDescription = tok.DataAtom == atom.Dd && afterItem
+			afterItem = false
+			if tok.DataAtom != atom.A {
+				continue
+			}
+
+			item, err := bookmarkItem(tok.Attr, source)
+			if err != nil {
+				return nil, err
+			}
+			current = item
+			title.Reset()
+
+		case html.EndTagToken:
+			inDescription, afterItem = false, false
+			if z.Token().DataAtom != atom.A || current == nil {
+				continue
+			}
+			current.Title = titleOrUntitled(title.String())
+			items = append(items, *current)
+			current = nil
+			afterItem = true
+
+		case html.TextToken:
+			switch {
+			case current != nil:
+				title.Write(z.Text())
+			case inDescription:
+				last := &items[len(items)-1]
+				last.Description = strings.TrimSpace(last.Description + string(z.Text()))
+			}
+		}
+	}
+}
+
+// bookmarkItem builds an item from the attributes of an <A> element.
+// It returns nil for anchors that are not http(s) bookmarks.
+func bookmarkItem(attrs []html.Attribute, source string) (*collector.Item, error) {
+	var link, added, tags string
+	for _, a := range attrs {
+		switch a.Key {
+		case "href":
+			link = strings.TrimSpace(a.Val)
+		case "add_date", "time_added":
+			added = a.Val
+		case "tags":
+			tags = a.Val
+		}
+	}
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		return nil, nil
+	}
+
+	date, err := unixDate(added)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", link, err)
+	}
+
+	return &collector.Item{
+		Link:       link,
+		Published:  date,
+		Collected:  date,
+		Source:     source,
+		Categories: splitTags(tags, ","),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadNetscapeHTML(t *testing.T) {
+	f, err := os.Open("testdata/bookmarks.html")
+	if err != nil {
+		t.Fatalf("cannot open fixture: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	items, err := ReadNetscapeHTML(f)
+	if err != nil {
+		t.Fatalf("ReadNetscapeHTML() error: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items (place: link skipped), got %d: %+v", len(items), items)
+	}
+
+	got := items[0]
+	if got.Link != "https://example.com/one" || got.Title != "Article & One" {
+		t.Errorf("items[0]: unexpected %+v", got)
+	}
+	if got.Description != "First bookmark description." {
+		t.Errorf("items[0].Description: got %q", got.Description)
+	}
+	if !slices.Equal(got.Categories, []string{"go", "web"}) {
+		t.Errorf("items[0].Categories: got %q", got.Categories)
+	}
+	if got.Published != "2025-02-28T10:00:00Z" || got.Collected != got.Published || got.Source != "bookmarks" {
+		t.Errorf("items[0]: unexpected date or source %+v", got)
+	}
+
+	if items[1].Description != "" {
+		t.Errorf("items[1].Description: got %q, want none", items[1].Description)
+	}
+	if items[2].Title != "Untitled" || items[2].Categories != nil {
+		t.Errorf("items[2]: unexpected %+v", items[2])
+	}
+}
+
+func TestReadNetscapeHTML_FolderDescription(t *testing.T) {
+	const doc = `<DL><p>
+    <DT><A HREF="https://example.com/one" ADD_DATE="1740736800">One</A>
+    <DT><H3>Reading</H3>
+    <DD>Folder description.
+    <DL><p>
+        <DT><A HREF="https://example.com/two" ADD_DATE="1740650400">Two</A>
+        <DT><A HREF="place:sort=8">Recent Tags</A>
+        <DD>Smart bookmark description.
+    </DL><p>
+</DL><p>`
+
+	items, err := ReadNetscapeHTML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ReadNetscapeHTML() error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d: %+v", len(items), items)
+	}
+	for _, item := range items {
+		if item.Description != "" {
+			t.Errorf("%s: got description %q, want none", item.Link, item.Description)
+		}
+	}
+}
+
+func TestRead_UnknownFormat(t *testing.T) {
+	_, err := Read("delicious", strings.NewReader(""))
+	if err == nil || !strings.Contains(err.Error(), "instapaper, netscape, pinboard, pocket") {
+		t.Errorf("Read() error = %v, want the supported formats listed", err)
+	}
+}
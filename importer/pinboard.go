@@ -0,0 +1,56 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// pinboardPost is a bookmark in Pinboard's JSON export. Pinboard calls the
+// title "description" and the description "extended".
+type pinboardPost struct {
+	Href        string `json:"href"`
+	Description string `json:"description"`
+	Extended    string `json:"extended"`
+	Time        string `json:"time"`
+	Tags        string `json:"tags"`
+}
+
+// ReadPinboardJSON reads Pinboard's JSON export (Settings → Backup → JSON).
+// Tags are separated by spaces.
+func ReadPinboardJSON(r io.Reader) ([]collector.Item, error) {
+	var posts []pinboardPost
+	if err := json.NewDecoder(r).Decode(&posts); err != nil {
+		return nil, fmt.Errorf("cannot read Pinboard JSON: %w", err)
+	}
+
+	items := make([]collector.Item, 0, len(posts))
+	for _, p := range posts {
+		link := strings.TrimSpace(p.Href)
+		if link == "" {
+			continue
+		}
+
+		t, err := collector.ParseDate(p.Time)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read Pinboard JSON: %s: %w", link, err)
+		}
+		date := t.UTC().Format(time.RFC3339)
+
+		items = append(items, collector.Item{
+			Title:       titleOrUntitled(p.Description),
+			Link:        link,
+			Description: strings.TrimSpace(p.Extended),
+			Published:   date,
+			Collected:   date,
+			Source:      "pinboard",
+			Categories:  splitTags(p.Tags, " "),
+		})
+	}
+
+	return items, nil
+}
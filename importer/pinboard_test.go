@@ -0,0 +1,48 @@
+package importer
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadPinboardJSON(t *testing.T) {
+	f, err := os.Open("testdata/pinboard.json")
+	if err != nil {
+		t.Fatalf("cannot open fixture: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	items, err := ReadPinboardJSON(f)
+	if err != nil {
+		t.Fatalf("ReadPinboardJSON() error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	got := items[0]
+	if got.Link != "https://example.com/one" || got.Title != "Pinboard One" || got.Description != "Saved for later." {
+		t.Errorf("items[0]: unexpected %+v", got)
+	}
+	if got.Published != "2025-02-28T10:00:00Z" || got.Source != "pinboard" {
+		t.Errorf("items[0]: unexpected date or source %+v", got)
+	}
+	if !slices.Equal(got.Categories, []string{"go", "web"}) {
+		t.Errorf("items[0].Categories: got %q, want duplicates dropped", got.Categories)
+	}
+	if items[1].Title != "Untitled" {
+		t.Errorf("items[1].Title: got %q, want %q", items[1].Title, "Untitled")
+	}
+}
+
+func TestReadPinboardJSON_InvalidDate(t *testing.T) {
+	data := `[{"href":"https://example.com/one","time":"someday"}]`
+
+	_, err := ReadPinboardJSON(strings.NewReader(data))
+	if err == nil || !strings.Contains(err.Error(), "https://example.com/one") {
+		t.Errorf("ReadPinboardJSON() error = %v, want it to name the bookmark", err)
+	}
+}
@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// ReadPocket reads a Pocket export: either the HTML file (ril_export.html)
+// or the CSV file of the newer export. The format is detected from the content.
+func ReadPocket(r io.Reader) ([]collector.Item, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(head, []byte("<")) {
+		return ReadPocketHTML(br)
+	}
+	return ReadPocketCSV(br)
+}
+
+// ReadPocketHTML reads Pocket's HTML export, a list of links with
+// TIME_ADDED and comma-separated TAGS attributes.
+func ReadPocketHTML(r io.Reader) ([]collector.Item, error) {
+	items, err := readBookmarkHTML(r, "pocket")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read Pocket HTML: %w", err)
+	}
+	return items, nil
+}
+
+// ReadPocketCSV reads Pocket's CSV export with the columns title, url,
+// time_added (unix seconds), tags (separated by "|") and status.
+func ReadPocketCSV(r io.Reader) ([]collector.Item, error) {
+	var items []collector.Item
+	err := readCSV(r, "url", func(row csvRow) error {
+		link := row.get("url")
+		if link == "" {
+			return nil
+		}
+
+		date, err := unixDate(row.get("time_added"))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", row.line, err)
+		}
+
+		items = append(items, collector.Item{
+			Title:      titleOrUntitled(row.get("title")),
+			Link:       link,
+			Published:  date,
+			Collected:  date,
+			Source:     "pocket",
+			Categories: splitTags(row.get("tags"), "|"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read Pocket CSV: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,38 @@
+package importer
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestReadPocket(t *testing.T) {
+	for _, fixture := range []string{"testdata/pocket.html", "testdata/pocket.csv"} {
+		f, err := os.Open(fixture)
+		if err != nil {
+			t.Fatalf("cannot open fixture: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+
+		items, err := Read("pocket", f)
+		if err != nil {
+			t.Fatalf("Read(%s) error: %v", fixture, err)
+		}
+
+		if len(items) != 2 {
+			t.Fatalf("%s: expected 2 items, got %d", fixture, len(items))
+		}
+		if items[0].Link != "https://example.com/one" || items[0].Title != "Pocket One" || items[0].Source != "pocket" {
+			t.Errorf("%s: unexpected items[0] %+v", fixture, items[0])
+		}
+		if items[0].Published != "2025-02-28T10:00:00Z" {
+			t.Errorf("%s: items[0].Published got %q", fixture, items[0].Published)
+		}
+		if !slices.Equal(items[0].Categories, []string{"go", "reading"}) {
+			t.Errorf("%s: items[0].Categories got %q", fixture, items[0].Categories)
+		}
+		if items[1].Categories != nil {
+			t.Errorf("%s: items[1].Categories got %q, want none", fixture, items[1].Categories)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package instapaper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// fakeAPI is a stand-in for the Instapaper API serving bookmarks of the unread folder.
+type fakeAPI struct {
+	bookmarks  []Bookmark
+	highlights []Highlight
+	requests   int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Path == "/api/1/oauth/access_token" {
+		if r.PostForm.Get("x_auth_username") != "reader" || r.PostForm.Get("x_auth_password") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`[{"type":"error","error_code":1040,"message":"Invalid xAuth credentials."}]`))
+			return
+		}
+		_, _ = w.Write([]byte("oauth_token=token&oauth_token_secret=token-secret"))
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Authorization"), `oauth_token="token"`) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`[{"type":"error","error_code":1041,"message":"Not logged in."}]`))
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/api/1.1/bookmarks/list":
+		f.list(w, r)
+	case strings.HasSuffix(r.URL.Path, "/highlights"):
+		_ = json.NewEncoder(w).Encode(f.highlights)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeAPI) list(w http.ResponseWriter, r *http.Request) {
+	have := make(map[string]bool)
+	for _, id := range strings.Split(r.PostForm.Get("have"), ",") {
+		have[id] = true
+	}
+	limit, _ := strconv.Atoi(r.PostForm.Get("limit"))
+
+	list := BookmarkList{Highlights: []Highlight{}}
+	if r.PostForm.Get("folder_id") == "unread" {
+		for _, b := range f.bookmarks {
+			if len(list.Bookmarks) == limit {
+				break
+			}
+			if !have[strconv.FormatInt(b.ID, 10)] {
+				list.Bookmarks = append(list.Bookmarks, b)
+			}
+		}
+		for _, h := range f.highlights {
+			for _, b := range list.Bookmarks {
+				if h.BookmarkID == b.ID {
+					list.Highlights = append(list.Highlights, h)
+				}
+			}
+		}
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+func newTestClient(t *testing.T, api http.Handler) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+
+	return &Client{ConsumerKey: "key", ConsumerSecret: "secret", BaseURL: srv.URL}
+}
+
+func TestLogin(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+
+	if err := c.Login(context.Background(), "reader", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+	if c.Token != "token" || c.TokenSecret != "token-secret" {
+		t.Errorf("Login() stored token %q / %q", c.Token, c.TokenSecret)
+	}
+}
+
+func TestLogin_InvalidCredentials(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+
+	err := c.Login(context.Background(), "reader", "wrong")
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Login() error = %v, want *Error", err)
+	}
+	if apiErr.Code != 1040 || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	api := &fakeAPI{highlights: []Highlight{{ID: 1, BookmarkID: 7, Text: "quoted"}}}
+	c := newTestClient(t, api)
+	c.Token, c.TokenSecret = "token", "token-secret"
+
+	highlights, err := c.Highlights(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Highlights() error: %v", err)
+	}
+	if len(highlights) != 1 || highlights[0].Text != "quoted" {
+		t.Errorf("unexpected highlights: %+v", highlights)
+	}
+}
+
+func TestSource_PagesThroughFolder(t *testing.T) {
+	api := &fakeAPI{}
+	for i := range pageSize + 2 {
+		api.bookmarks = append(api.bookmarks, Bookmark{
+			ID:    int64(i + 1),
+			URL:   fmt.Sprintf("https://example.com/%d", i+1),
+			Title: fmt.Sprintf("Article %d", i+1),
+			Time:  1740736800,
+		})
+	}
+	api.bookmarks[0].Starred = "1"
+	api.bookmarks[0].Progress = 0.5
+	api.highlights = []Highlight{
+		{ID: 2, BookmarkID: 1, Text: "second", Position: 1},
+		{ID: 1, BookmarkID: 1, Text: "first", Note: "note", Time: 1740740400, Position: 0},
+	}
+
+	c := newTestClient(t, api)
+	c.Token, c.TokenSecret = "token", "token-secret"
+
+	items, err := (&Source{Client: c, Folders: []string{"unread"}}).Items(context.Background())
+	if err != nil {
+		t.Fatalf("Items() error: %v", err)
+	}
+
+	if len(items) != pageSize+2 {
+		t.Fatalf("expected %d items, got %d", pageSize+2, len(items))
+	}
+	if api.requests != 2 {
+		t.Errorf("expected 2 requests, got %d", api.requests)
+	}
+
+	got := items[0]
+	if got.GUID != "1" || got.Link != "https://example.com/1" || got.Published != "2025-02-28T10:00:00Z" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if !got.Starred || got.Progress != 0.5 {
+		t.Errorf("reading state not mapped: %+v", got)
+	}
+	want := []collector.Highlight{
+		{Text: "first", Note: "note", Created: "2025-02-28T11:00:00Z"},
+		{Text: "second"},
+	}
+	if len(got.Highlights) != 2 || got.Highlights[0] != want[0] || got.Highlights[1] != want[1] {
+		t.Errorf("Highlights: got %+v, want %+v", got.Highlights, want)
+	}
+}
+
+func TestSource_WithCollector(t *testing.T) {
+	api := &fakeAPI{bookmarks: []Bookmark{
+		{ID: 1, URL: "https://example.com/one", Title: "One", Time: 1740736800},
+	}}
+	c := newTestClient(t, api)
+	if err := c.Login(context.Background(), "reader", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+
+	data := collector.New(filepath.Join(t.TempDir(), "data.json"),
+		collector.WithSources(&Source{Client: c, Folders: []string{"unread"}}))
+
+	updated, err := data.UpdateFeedsContext(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("UpdateFeedsContext() error: %v", err)
+	}
+	if !updated || len(data.Items) != 1 {
+		t.Fatalf("expected 1 new item, got updated=%v items=%+v", updated, data.Items)
+	}
+	if data.Items[0].Source != "instapaper" {
+		t.Errorf("Source: got %q, want %q", data.Items[0].Source, "instapaper")
+	}
+}
+
+func TestSource_NotLoggedIn(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+
+	data := collector.New(filepath.Join(t.TempDir(), "data.json"),
+		collector.WithSources(&Source{Client: c}))
+
+	_, err := data.UpdateFeedsContext(context.Background(), nil)
+
+	var feedErr *collector.FeedError
+	if !errors.As(err, &feedErr) || feedErr.URL != "instapaper" {
+		t.Fatalf("UpdateFeedsContext() error = %v, want *FeedError for the source", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 1041 {
+		t.Errorf("expected the API error to be wrapped, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package instapaper
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// authorization returns the OAuth 1.0a Authorization header (HMAC-SHA1,
+// RFC 5849) for a request with the given form parameters.
+func (c *Client) authorization(method, rawURL string, params url.Values) (string, error) {
+	oauth := map[string]string{
+		"oauth_consumer_key":     c.ConsumerKey,
+		"oauth_nonce":            c.newNonce(),
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(c.clock().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+	if c.Token != "" {
+		oauth["oauth_token"] = c.Token
+	}
+
+	signature, err := signature(method, rawURL, params, oauth, c.ConsumerSecret, c.TokenSecret)
+	if err != nil {
+		return "", err
+	}
+	oauth["oauth_signature"] = signature
+
+	keys := make([]string, 0, len(oauth))
+	for k := range oauth {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = percentEncode(k) + `="` + percentEncode(oauth[k]) + `"`
+	}
+	return "OAuth " + strings.Join(parts, ", "), nil
+}
+
+// signature computes the HMAC-SHA1 signature over the signature base string
+// built from the method, the URL without query and all parameters,
+// including those of the URL query.
+func signature(method, rawURL string, params url.Values, oauth map[string]string, consumerSecret, tokenSecret string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	var pairs [][2]string
+	add := func(k, v string) {
+		pairs = append(pairs, [2]string{percentEncode(k), percentEncode(v)})
+	}
+	for k, vs := range u.Query() {
+		for _, v := range vs {
+			add(k, v)
+		}
+	}
+	for k, vs := range params {
+		for _, v := range vs {
+			add(k, v)
+		}
+	}
+	for k, v := range oauth {
+		add(k, v)
+	}
+	slices.SortFunc(pairs, func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+
+	normalized := make([]string, len(pairs))
+	for i, p := range pairs {
+		normalized[i] = p[0] + "=" + p[1]
+	}
+
+	base := strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + u.EscapedPath()
+	baseString := strings.ToUpper(method) + "&" + percentEncode(base) + "&" + percentEncode(strings.Join(normalized, "&"))
+
+	mac := hmac.New(sha1.New, []byte(percentEncode(consumerSecret)+"&"+percentEncode(tokenSecret)))
+	mac.Write([]byte(baseString))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// percentEncode escapes s as required by RFC 5849: everything except
+// the unreserved characters A-Z a-z 0-9 - . _ ~.
+func percentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '.', ch == '_', ch == '~':
+			b.WriteByte(ch)
+		default:
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{ch})))
+		}
+	}
+	return b.String()
+}
+
+func (c *Client) newNonce() string {
+	if c.nonce != nil {
+		return c.nonce()
+	}
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,66 @@
+package instapaper
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSignature uses the worked example from Twitter's "Creating a signature" guide.
+func TestSignature(t *testing.T) {
+	params := url.Values{"status": {"Hello Ladies + Gentlemen, a signed OAuth request!"}}
+	oauth := map[string]string{
+		"oauth_consumer_key":     "xvz1evFS4wEEPTGEFPHBog",
+		"oauth_nonce":            "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "1318622958",
+		"oauth_token":            "370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb",
+		"oauth_version":          "1.0",
+	}
+
+	got, err := signature("post", "https://api.twitter.com/1.1/statuses/update.json?include_entities=true", params, oauth,
+		"kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw", "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE")
+	if err != nil {
+		t.Fatalf("signature() error: %v", err)
+	}
+	if want := "hCtSmYh+iHYCEqBWrE7C7hYmtUk="; got != want {
+		t.Errorf("signature() = %q, want %q", got, want)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	c := &Client{
+		ConsumerKey:    "key",
+		ConsumerSecret: "secret",
+		now:            func() time.Time { return time.Unix(1318622958, 0) },
+		nonce:          func() string { return "nonce" },
+	}
+
+	got, err := c.authorization("POST", "https://www.instapaper.com/api/1/oauth/access_token", nil)
+	if err != nil {
+		t.Fatalf("authorization() error: %v", err)
+	}
+
+	for _, want := range []string{`oauth_consumer_key="key"`, `oauth_nonce="nonce"`, `oauth_timestamp="1318622958"`, `oauth_signature="`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("authorization() = %q, should contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "oauth_token=") {
+		t.Errorf("authorization() = %q, should not send a token before login", got)
+	}
+}
+
+func TestPercentEncode(t *testing.T) {
+	tests := map[string]string{
+		"Ladies + Gentlemen": "Ladies%20%2B%20Gentlemen",
+		"a-b.c_d~e":          "a-b.c_d~e",
+		"☃":                  "%E2%98%83",
+	}
+	for in, want := range tests {
+		if got := percentEncode(in); got != want {
+			t.Errorf("percentEncode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package instapaper
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+// pageSize is the largest page bookmarks/list returns.
+const pageSize = 500
+
+// DefaultFolders are the folders collected by a Source without Folders.
+var DefaultFolders = []string{"unread", "archive"}
+
+// Source collects all bookmarks of the configured folders through the API.
+// It implements collector.Source; add it with collector.WithSources.
+type Source struct {
+	Client *Client
+	// Folders overrides DefaultFolders.
+	Folders []string
+}
+
+// Name implements collector.Source.
+func (s *Source) Name() string {
+	return "instapaper"
+}
+
+// Items pages through every folder and maps the bookmarks to items.
+// The bookmark ID is used as the item GUID.
+func (s *Source) Items(ctx context.Context) ([]collector.Item, error) {
+	folders := s.Folders
+	if len(folders) == 0 {
+		folders = DefaultFolders
+	}
+
+	var items []collector.Item
+	for _, folder := range folders {
+		bookmarks, highlights, err := s.folder(ctx, folder)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bookmarks {
+			if item, ok := toItem(b, highlights[b.ID]); ok {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// folder returns all bookmarks of a folder, requesting pages with the IDs
+// received so far until a page comes back short or without new bookmarks.
+func (s *Source) folder(ctx context.Context, folder string) ([]Bookmark, map[int64][]Highlight, error) {
+	var bookmarks []Bookmark
+	var have []int64
+	seen := make(map[int64]struct{})
+	highlights := make(map[int64][]Highlight)
+
+	for {
+		page, err := s.Client.Bookmarks(ctx, ListOptions{Folder: folder, Limit: pageSize, Have: have})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		added := 0
+		for _, b := range page.Bookmarks {
+			if _, ok := seen[b.ID]; ok {
+				continue
+			}
+			seen[b.ID] = struct{}{}
+			bookmarks = append(bookmarks, b)
+			have = append(have, b.ID)
+			added++
+		}
+		for _, h := range page.Highlights {
+			highlights[h.BookmarkID] = append(highlights[h.BookmarkID], h)
+		}
+
+		if added == 0 || len(page.Bookmarks) < pageSize {
+			return bookmarks, highlights, nil
+		}
+	}
+}
+
+func toItem(b Bookmark, highlights []Highlight) (collector.Item, bool) {
+	link := strings.TrimSpace(b.URL)
+	if link == "" {
+		return collector.Item{}, false
+	}
+
+	title := strings.TrimSpace(b.Title)
+	if title == "" {
+		title = "Untitled"
+	}
+
+	slices.SortFunc(highlights, func(a, b Highlight) int { return a.Position - b.Position })
+
+	item := collector.Item{
+		Title:       title,
+		Link:        link,
+		Description: b.Description,
+		Published:   cmp.Or(unixTime(b.Time), time.Now().UTC().Format(time.RFC3339)),
+		GUID:        strconv.FormatInt(b.ID, 10),
+		Starred:     b.Starred == "1",
+		Progress:    b.Progress,
+	}
+	for _, h := range highlights {
+		item.Highlights = append(item.Highlights, collector.Highlight{
+			Text:    h.Text,
+			Note:    h.Note,
+			Created: unixTime(h.Time),
+		})
+	}
+
+	return item, true
+}
+
+func unixTime(t float64) string {
+	if t <= 0 {
+		return ""
+	}
+	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,54 @@
+// Package htmltext extracts the text of HTML fragments such as feed descriptions.
+package htmltext
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// blocks are the elements whose text is separated from their neighbours.
+var blocks = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "tr": true, "td": true, "th": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "hr": true, "img": true,
+}
+
+// Text returns the text of the HTML fragment s with entities decoded and
+// script and style elements dropped. Block elements are separated by spaces.
+// Plain text passes through unchanged.
+func Text(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	skip := ""
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			if skip == "" {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			if string(name) == "script" || string(name) == "style" {
+				skip = string(name)
+			}
+			if blocks[string(name)] {
+				b.WriteByte(' ')
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if string(name) == skip {
+				skip = ""
+			}
+			if blocks[string(name)] {
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
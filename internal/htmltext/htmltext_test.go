@@ -0,0 +1,19 @@
+package htmltext
+
+import "testing"
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain & simple", "plain & simple"},
+		{"<p>One</p><p>Two <b>three</b></p>", " One  Two three "},
+		{"Fish &amp; chips<script>track()</script>", "Fish & chips"},
+		{"<style>p { color: red }</style>Text", "Text"},
+	}
+	for _, tt := range tests {
+		if got := Text(tt.in); got != tt.want {
+			t.Errorf("Text(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	Summary       string               `json:"summary"`
+	ContentText   string               `json:"content_text"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	ContentHTML   string               `json:"content_html"`
+	Tags          []string             `json:"tags"`
+	Author        *jsonFeedAuthor      `json:"author"`
+	Authors       []jsonFeedAuthor     `json:"authors"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MIMEType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// ParseJSONFeed parses a JSON Feed 1.0 or 1.1 document into items.
+// The item link is url, falling back to external_url; the description is
+// summary, falling back to content_text.
+func ParseJSONFeed(data []byte) ([]Item, error) {
+	return Parser{}.ParseJSONFeed(data)
+}
+
+// ParseJSONFeed parses a JSON Feed document into items. See the package-level ParseJSONFeed.
+// JSON Feed has no feed-level date, so DateFromFeed falls back to the current time.
+func (p Parser) ParseJSONFeed(data []byte) ([]Item, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON Feed: %w", err)
+	}
+
+	if !strings.HasPrefix(feed.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("failed to parse JSON Feed: unsupported version %q", feed.Version)
+	}
+
+	items := make([]Item, 0, len(feed.Items))
+	for _, ji := range feed.Items {
+		link := strings.TrimSpace(ji.URL)
+		if link == "" {
+			link = strings.TrimSpace(ji.ExternalURL)
+		}
+		if link == "" {
+			continue
+		}
+
+		title := strings.TrimSpace(ji.Title)
+		if title == "" {
+			title = "Untitled"
+		}
+
+		date := ji.DatePublished
+		if date == "" {
+			date = ji.DateModified
+		}
+		published, ok := p.itemDate(link, date, "")
+		if !ok {
+			continue
+		}
+
+		description := ji.Summary
+		if description == "" {
+			description = ji.ContentText
+		}
+
+		items = append(items, Item{
+			Title:       title,
+			Link:        link,
+			Description: description,
+			Published:   published,
+			GUID:        strings.TrimSpace(ji.ID),
+			Author:      ji.author(),
+			Categories:  trimAll(ji.Tags),
+			Content:     ji.ContentHTML,
+			Enclosures:  ji.enclosures(),
+		})
+	}
+
+	return items, nil
+}
+
+// author joins the JSON Feed 1.1 authors, falling back to the 1.0 author.
+func (ji jsonFeedItem) author() string {
+	authors := ji.Authors
+	if len(authors) == 0 && ji.Author != nil {
+		authors = []jsonFeedAuthor{*ji.Author}
+	}
+
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (ji jsonFeedItem) enclosures() []Enclosure {
+	var enclosures []Enclosure
+	for _, a := range ji.Attachments {
+		if u := strings.TrimSpace(a.URL); u != "" {
+			enclosures = append(enclosures, Enclosure{URL: u, Type: a.MIMEType, Length: a.SizeInBytes})
+		}
+	}
+	return enclosures
+}
@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseJSONFeed_ValidFeed(t *testing.T) {
+	data, err := os.ReadFile("testdata/feed.json")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	items, err := ParseJSONFeed(data)
+	if err != nil {
+		t.Fatalf("ParseJSONFeed() error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items (item without url skipped), got %d", len(items))
+	}
+
+	if items[0].Link != "https://example.net/json-one" {
+		t.Errorf("items[0].Link: got %q, want %q", items[0].Link, "https://example.net/json-one")
+	}
+	if items[0].Description != "First JSON summary." {
+		t.Errorf("items[0].Description: got %q, want %q", items[0].Description, "First JSON summary.")
+	}
+	if items[0].Published != "2025-02-28T08:00:00Z" {
+		t.Errorf("items[0].Published: got %q, want %q", items[0].Published, "2025-02-28T08:00:00Z")
+	}
+
+	if items[1].Link != "https://example.net/json-two" {
+		t.Errorf("items[1].Link: got %q, want %q (external_url fallback)", items[1].Link, "https://example.net/json-two")
+	}
+	if items[1].Description != "Second JSON content." {
+		t.Errorf("items[1].Description: got %q, want %q (content_text fallback)", items[1].Description, "Second JSON content.")
+	}
+	if items[1].Published != "2025-02-27T08:30:00Z" {
+		t.Errorf("items[1].Published: got %q, want %q (date_modified fallback)", items[1].Published, "2025-02-27T08:30:00Z")
+	}
+}
+
+func TestParseJSONFeed_Metadata(t *testing.T) {
+	data, err := os.ReadFile("testdata/feed.json")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	items, err := ParseJSONFeed(data)
+	if err != nil {
+		t.Fatalf("ParseJSONFeed() error: %v", err)
+	}
+
+	got := items[0]
+	if got.Author != "Jane Doe" {
+		t.Errorf("Author: got %q, want %q", got.Author, "Jane Doe")
+	}
+	if !slices.Equal(got.Categories, []string{"json", "feeds"}) {
+		t.Errorf("Categories: got %q", got.Categories)
+	}
+	if got.Content != "<p>First JSON content.</p>" {
+		t.Errorf("Content: got %q", got.Content)
+	}
+	want := []Enclosure{{URL: "https://example.net/json-one.png", Type: "image/png", Length: 512}}
+	if !slices.Equal(got.Enclosures, want) {
+		t.Errorf("Enclosures: got %+v, want %+v", got.Enclosures, want)
+	}
+
+	if items[1].Author != "John Roe" {
+		t.Errorf("items[1].Author: got %q, want %q (JSON Feed 1.0 author)", items[1].Author, "John Roe")
+	}
+}
+
+func TestParseJSONFeed_UnsupportedVersion(t *testing.T) {
+	data := []byte(`{"version": "2", "items": []}`)
+
+	if _, err := ParseJSONFeed(data); err == nil {
+		t.Error("ParseJSONFeed() should return error for unknown version")
+	}
+}
+
+func TestParseFeedType_ContentType(t *testing.T) {
+	data, err := os.ReadFile("testdata/feed.json")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	for _, contentType := range []string{"application/feed+json; charset=utf-8", "text/plain", ""} {
+		items, err := ParseFeedType(contentType, data)
+		if err != nil {
+			t.Fatalf("ParseFeedType(%q) error: %v", contentType, err)
+		}
+		if len(items) != 2 {
+			t.Errorf("ParseFeedType(%q): expected 2 items, got %d", contentType, len(items))
+		}
+	}
+}
+
+func TestParseFeedType_MislabeledXML(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		contentType string
+		want        string
+	}{
+		{"testdata/atom.xml", "application/rss+xml", "https://example.org/atom-one"},
+		{"testdata/feed.xml", "application/atom+xml", "https://example.com/article-one"},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.fixture)
+		if err != nil {
+			t.Fatalf("cannot read fixture: %v", err)
+		}
+
+		items, err := ParseFeedType(tt.contentType, data)
+		if err != nil {
+			t.Fatalf("ParseFeedType(%q, %s) error: %v", tt.contentType, tt.fixture, err)
+		}
+		if len(items) == 0 || items[0].Link != tt.want {
+			t.Errorf("ParseFeedType(%q, %s): unexpected items %+v", tt.contentType, tt.fixture, items)
+		}
+	}
+}
+
+func TestUpdate_JSONFeed(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.json")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json")
+		_, _ = w.Write(feedData)
+	}))
+	defer server.Close()
+
+	c := New(filepath.Join(t.TempDir(), "data.json"))
+	if _, err := c.Update(server.URL); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if len(c.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(c.Items))
+	}
+}
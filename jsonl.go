@@ -0,0 +1,227 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JSONLStore keeps items in an append-only JSON Lines file, one item per line,
+// so that each run only adds lines to the file. A later line for the same link
+// replaces the earlier one. Collection metadata is kept in a small sidecar file
+// next to it (data.jsonl -> data.meta.json).
+type JSONLStore struct {
+	fileName string
+	metaName string
+	loaded   bool
+	// size is the length of the lines read from the file. torn is set when
+	// an incomplete last line follows them, unterminated when the last line
+	// read lacks its newline.
+	size         int64
+	torn         bool
+	unterminated bool
+	itemIndex
+}
+
+func NewJSONLStore(fileName string) *JSONLStore {
+	ext := filepath.Ext(fileName)
+	return &JSONLStore{
+		fileName: fileName,
+		metaName: strings.TrimSuffix(fileName, ext) + ".meta.json",
+	}
+}
+
+// Load streams the items file line by line and reads the metadata file.
+func (s *JSONLStore) Load(ctx context.Context) (Meta, []Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Meta{}, nil, err
+	}
+
+	s.loaded = false
+	s.size, s.torn, s.unterminated = 0, false, false
+	s.itemIndex = newItemIndex()
+
+	meta, err := s.loadMeta()
+	if err != nil {
+		return Meta{}, nil, err
+	}
+
+	file, err := os.Open(s.fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.loaded = true
+			return meta, nil, nil
+		}
+		return Meta{}, nil, fmt.Errorf("cannot open file %q: %w", s.fileName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	r := bufio.NewReader(file)
+	for lineNo := 1; ; lineNo++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Meta{}, nil, fmt.Errorf("cannot read file %q: %w", s.fileName, err)
+		}
+
+		complete := bytes.HasSuffix(line, []byte("\n"))
+		if !complete && len(bytes.TrimSpace(line)) == 0 {
+			s.torn = len(line) > 0
+			break
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var item Item
+			if err := json.Unmarshal(line, &item); err != nil {
+				if !complete {
+					// A run interrupted while appending leaves a partial last
+					// line; the next Append cuts it off.
+					log.Printf("ignoring incomplete last line %d in %q", lineNo, s.fileName)
+					s.torn = true
+					break
+				}
+				return Meta{}, nil, fmt.Errorf("invalid JSON in %q line %d: %w", s.fileName, lineNo, err)
+			}
+			if item.Link == "" {
+				log.Printf("skipping item with empty link: %q", item.Title)
+			} else {
+				s.put(item)
+			}
+		}
+		s.size += int64(len(line))
+		s.unterminated = !complete
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if lineNo%1000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return Meta{}, nil, err
+			}
+		}
+	}
+
+	s.sort()
+	s.loaded = true
+
+	return meta, slices.Clone(s.items), nil
+}
+
+// Append writes items as new lines at the end of the file and replaces the metadata file.
+// An incomplete last line left by an interrupted run is removed first.
+func (s *JSONLStore) Append(ctx context.Context, meta Meta, items []Item) error {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(items) > 0 {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+
+		if err := s.appendLines(buf.Bytes()); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			s.put(item)
+		}
+		s.sort()
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "    ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(meta); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(ctx, s.metaName, buf.Bytes())
+}
+
+func (s *JSONLStore) Range(ctx context.Context, from, to time.Time) ([]Item, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	return s.rangeItems(from, to), nil
+}
+
+func (s *JSONLStore) Lookup(ctx context.Context, link string) (Item, bool, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return Item{}, false, err
+	}
+	item, ok := s.lookup(link)
+	return item, ok, nil
+}
+
+func (s *JSONLStore) ensureLoaded(ctx context.Context) error {
+	if s.loaded {
+		return ctx.Err()
+	}
+	_, _, err := s.Load(ctx)
+	return err
+}
+
+func (s *JSONLStore) loadMeta() (Meta, error) {
+	data, err := os.ReadFile(s.metaName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Meta{}, nil
+		}
+		return Meta{}, fmt.Errorf("cannot read file %q: %w", s.metaName, err)
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Meta{}, fmt.Errorf("invalid JSON in %q: %w", s.metaName, err)
+	}
+	return meta, nil
+}
+
+func (s *JSONLStore) appendLines(data []byte) error {
+	file, err := os.OpenFile(s.fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot open file %q: %w", s.fileName, err)
+	}
+
+	if s.torn {
+		if err := file.Truncate(s.size); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("cannot truncate file %q: %w", s.fileName, err)
+		}
+	}
+	if s.unterminated {
+		data = append([]byte("\n"), data...)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot append to file %q: %w", s.fileName, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("cannot close file %q: %w", s.fileName, err)
+	}
+
+	s.size += int64(len(data))
+	s.torn, s.unterminated = false, false
+	return nil
+}
@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSONLStore_AppendsLines(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.jsonl")
+
+	s := NewJSONLStore(path)
+	meta := Meta{Title: "Test", Updated: "2025-03-01T00:00:00Z"}
+	if err := s.Append(ctx, meta, []Item{{Title: "One", Link: "https://example.com/one", Published: "2025-02-27T10:00:00Z"}}); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+
+	if err := s.Append(ctx, meta, []Item{{Title: "Two", Link: "https://example.com/two", Published: "2025-02-26T10:00:00Z"}}); err != nil {
+		t.Fatalf("second Append() error: %v", err)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+
+	if !bytes.HasPrefix(after, before) {
+		t.Error("Append() should not rewrite existing lines")
+	}
+	if lines := strings.Count(string(after), "\n"); lines != 2 {
+		t.Errorf("expected 2 lines, got %d", lines)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "data.meta.json")); err != nil {
+		t.Errorf("metadata file should be written: %v", err)
+	}
+}
+
+func TestJSONLStore_InvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	data := `{"title": "One", "link": "https://example.com/one"}
+{broken
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := NewJSONLStore(path).Load(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error naming line 2, got: %v", err)
+	}
+}
+
+func TestJSONLStore_IncompleteLastLine(t *testing.T) {
+	ctx := context.Background()
+	meta := Meta{Title: "Test"}
+	two := Item{Title: "Two", Link: "https://example.com/two", Published: "2025-02-28T10:00:00Z"}
+
+	tests := []struct {
+		name  string
+		data  string
+		items int
+	}{
+		{"torn", `{"title": "One", "link": "https://example.com/one", "published": "2025-02-27T10:00:00Z"}` + "\n" + `{"title": "Par`, 2},
+		{"unterminated", `{"title": "One", "link": "https://example.com/one", "published": "2025-02-27T10:00:00Z"}`, 2},
+		{"only torn", `{"title": "Par`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.jsonl")
+			if err := os.WriteFile(path, []byte(tt.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			s := NewJSONLStore(path)
+			if _, _, err := s.Load(ctx); err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if err := s.Append(ctx, meta, []Item{two}); err != nil {
+				t.Fatalf("Append() error: %v", err)
+			}
+
+			_, items, err := NewJSONLStore(path).Load(ctx)
+			if err != nil {
+				t.Fatalf("Load() after Append() error: %v", err)
+			}
+			if len(items) != tt.items || items[len(items)-1].Link != two.Link {
+				t.Errorf("expected %d items ending with the appended one, got %+v", tt.items, items)
+			}
+		})
+	}
+}
+
+func TestJSONLStore_AppendCanceled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	s := NewJSONLStore(path)
+	if _, _, err := s.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Append(ctx, Meta{}, []Item{{Title: "One", Link: "https://example.com/one"}}); err == nil {
+		t.Fatal("Append() should fail with a canceled context")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Append() should not write with a canceled context")
+	}
+}
+
+func TestUpdate_WithJSONLStore(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	c := New(path, WithStore(NewJSONLStore(path)), WithFetcher(&stubFetcher{body: feedData}))
+	if _, err := c.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	c2 := New(path, WithStore(NewJSONLStore(path)), WithFetcher(&stubFetcher{body: feedData}))
+	added, err := c2.Update("stub://feed")
+	if err != nil {
+		t.Fatalf("second Update() error: %v", err)
+	}
+	if added {
+		t.Error("second Update() should not add items")
+	}
+	if len(c2.Items) != 3 {
+		t.Errorf("expected 3 items, got %d", len(c2.Items))
+	}
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Option configures a Collector created with New.
+type Option func(*Collector)
+
+// WithFetcher replaces the default fetcher, which handles http(s) URLs,
+// file:// URLs and "-" for standard input. Options configuring the
+// HTTP fetcher have no effect once a custom fetcher is set.
+func WithFetcher(f Fetcher) Option {
+	return func(c *Collector) {
+		c.fetcher = f
+	}
+}
+
+// WithHTTPClient sets the client used for http(s) feeds,
+// e.g. one with a proxy, mTLS or a recording transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Collector) {
+		c.http.Client = client
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with http(s) feed requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Collector) {
+		c.http.UserAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets the retry policy for http(s) feed requests.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Collector) {
+		c.http.Retry = &p
+	}
+}
+
+// WithConcurrency limits the number of feeds fetched at the same time.
+func WithConcurrency(n int) Option {
+	return func(c *Collector) {
+		c.concurrency = n
+	}
+}
+
+// WithStore replaces the default JSON file store.
+func WithStore(s Store) Option {
+	return func(c *Collector) {
+		c.store = s
+	}
+}
+
+// WithCanonicalizer sets how links are normalized for deduplication.
+// Canonicalizer{} compares links verbatim.
+func WithCanonicalizer(canon Canonicalizer) Option {
+	return func(c *Collector) {
+		c.canon = canon
+	}
+}
+
+// WithUpdateTracking makes Update merge changed titles and descriptions from
+// the feeds into already collected items, stamping them with Item.Modified.
+// The modified items are reported by Collector.Changed.
+func WithUpdateTracking(enabled bool) Option {
+	return func(c *Collector) {
+		c.trackUpdates = enabled
+	}
+}
+
+// WithParser sets the parser for fetched feeds, e.g. to change the
+// fallback for items with missing or unparseable dates.
+// Feeds are parsed concurrently, but calls to p.Warn are serialized.
+func WithParser(p Parser) Option {
+	return func(c *Collector) {
+		if warn := p.Warn; warn != nil {
+			var mu sync.Mutex
+			p.Warn = func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				warn(err)
+			}
+		}
+		c.parser = p
+	}
+}
+
+// WithSources adds sources that are collected on every Update together with the feeds.
+func WithSources(sources ...Source) Option {
+	return func(c *Collector) {
+		c.sources = append(c.sources, sources...)
+	}
+}
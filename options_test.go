@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type stubFetcher struct {
+	body     []byte
+	requests []FetchRequest
+}
+
+func (f *stubFetcher) Fetch(_ context.Context, req FetchRequest) (*FetchResponse, error) {
+	f.requests = append(f.requests, req)
+	return &FetchResponse{Body: f.body}, nil
+}
+
+func TestNew_WithFetcher(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	f := &stubFetcher{body: feedData}
+	c := New(filepath.Join(t.TempDir(), "data.json"), WithFetcher(f))
+
+	if _, err := c.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if len(f.requests) != 1 || f.requests[0].URL != "stub://feed" {
+		t.Errorf("unexpected fetcher requests: %+v", f.requests)
+	}
+	if len(c.Items) != 3 {
+		t.Errorf("expected 3 items, got %d", len(c.Items))
+	}
+}
+
+func TestNew_WithHTTPClientAndUserAgent(t *testing.T) {
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write(feedData)
+	}))
+	defer server.Close()
+
+	c := New(filepath.Join(t.TempDir(), "data.json"),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("links-bot/1.0"),
+	)
+	if _, err := c.Update(server.URL); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if userAgent != "links-bot/1.0" {
+		t.Errorf("User-Agent: got %q, want %q", userAgent, "links-bot/1.0")
+	}
+}
+
+func TestUpdate_FileAndStdin(t *testing.T) {
+	abs, err := filepath.Abs("testdata/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonData, err := os.ReadFile("testdata/feed.json")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	c := New(path, WithFetcher(defaultFetcher{
+		http: &HTTPFetcher{},
+		file: &FileFetcher{Stdin: strings.NewReader(string(jsonData))},
+	}))
+
+	if _, err := c.UpdateFeeds([]Feed{{URL: "file://" + abs}, {Name: "stdin", URL: "-"}}); err != nil {
+		t.Fatalf("UpdateFeeds() error: %v", err)
+	}
+	if len(c.Items) != 5 {
+		t.Fatalf("expected 5 items from file and stdin, got %d", len(c.Items))
+	}
+
+	added, err := New(path).Update("file://" + abs)
+	if err != nil {
+		t.Fatalf("second Update() error: %v", err)
+	}
+	if added {
+		t.Error("unchanged file should be reported as not modified")
+	}
+}
+
+func TestUpdate_UnsupportedScheme(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "data.json"))
+
+	if _, err := c.Update("ftp://example.com/feed.xml"); err == nil {
+		t.Error("Update() should return error for unsupported URL scheme")
+	}
+}
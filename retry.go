@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed feed requests are retried.
+// Network errors and 429, 502, 503 and 504 responses are retried with
+// exponential backoff and jitter; a Retry-After header overrides the backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; it doubles on each retry.
+	// Zero uses the DefaultRetryPolicy value.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than MaxDelay is not waited for.
+	// Zero uses the DefaultRetryPolicy value.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by FetchRSS and Collector.Update.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// statusError is returned for unexpected HTTP status codes.
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("RSS feed returned status %d", e.code)
+}
+
+func (e *statusError) retryable() bool {
+	switch e.code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// do calls fn until it succeeds, returns a non-retryable error,
+// the policy runs out of attempts or ctx is done.
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || attempt+1 >= p.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay, ok := p.delay(attempt, err)
+		if !ok {
+			return err
+		}
+		if serr := sleep(ctx, delay); serr != nil {
+			return errors.Join(err, serr)
+		}
+	}
+}
+
+// delay returns how long to wait before retrying after err,
+// or false if err should not be retried.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+
+	var se *statusError
+	if errors.As(err, &se) {
+		if !se.retryable() {
+			return 0, false
+		}
+		if se.retryAfter > 0 {
+			if se.retryAfter > p.MaxDelay {
+				return 0, false
+			}
+			return se.retryAfter, true
+		}
+	}
+
+	backoff := p.BaseDelay << attempt
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+
+	// Equal jitter: wait at least half of the backoff.
+	half := backoff / 2
+	return half + rand.N(backoff-half+1), true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(s string, now time.Time) time.Duration {
+	if s == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
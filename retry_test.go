@@ -0,0 +1,159 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func withoutSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = orig })
+	return &delays
+}
+
+func TestFetchRSS_RetriesTransientErrors(t *testing.T) {
+	delays := withoutSleep(t)
+
+	feedData, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write(feedData)
+		}
+	}))
+	defer server.Close()
+
+	body, err := FetchRSS(server.URL)
+	if err != nil {
+		t.Fatalf("FetchRSS() error: %v", err)
+	}
+	if len(body) == 0 {
+		t.Error("FetchRSS() returned empty body")
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+	if len(*delays) != 2 || (*delays)[0] != 2*time.Second {
+		t.Errorf("expected Retry-After of 2s to be honored first, got delays %v", *delays)
+	}
+}
+
+func TestFetchRSS_DoesNotRetryClientErrors(t *testing.T) {
+	withoutSleep(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := FetchRSS(server.URL); err == nil {
+		t.Fatal("FetchRSS() should return error for 404")
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request for non-retryable status, got %d", requests)
+	}
+}
+
+func TestRetryPolicy_GivesUp(t *testing.T) {
+	delays := withoutSleep(t)
+
+	p := RetryPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	attempts := 0
+	err := p.do(context.Background(), func() error {
+		attempts++
+		return errors.New("connection reset")
+	})
+	if err == nil {
+		t.Fatal("do() should return the last error")
+	}
+	if attempts != 4 {
+		t.Errorf("expected 4 attempts, got %d", attempts)
+	}
+	for i, d := range *delays {
+		if d > p.MaxDelay {
+			t.Errorf("delay %d: %v exceeds MaxDelay %v", i, d, p.MaxDelay)
+		}
+	}
+}
+
+func TestRetryPolicy_DefaultDelays(t *testing.T) {
+	delays := withoutSleep(t)
+
+	p := RetryPolicy{MaxAttempts: 3}
+	attempts := 0
+	_ = p.do(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			return &statusError{code: http.StatusTooManyRequests, retryAfter: 5 * time.Second}
+		}
+		return errors.New("connection reset")
+	})
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts with only MaxAttempts set, got %d", attempts)
+	}
+	if (*delays)[0] != 5*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %v", (*delays)[0])
+	}
+	if d := (*delays)[1]; d < DefaultRetryPolicy.BaseDelay || d > DefaultRetryPolicy.MaxDelay {
+		t.Errorf("expected a backoff from the default policy, got %v", d)
+	}
+}
+
+func TestRetryPolicy_RetryAfterBeyondMaxDelay(t *testing.T) {
+	withoutSleep(t)
+
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	attempts := 0
+	_ = p.do(context.Background(), func() error {
+		attempts++
+		return &statusError{code: http.StatusServiceUnavailable, retryAfter: time.Hour}
+	})
+	if attempts != 1 {
+		t.Errorf("expected no retry when Retry-After exceeds MaxDelay, got %d attempts", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 2, 28, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"Fri, 28 Feb 2025 10:00:30 GMT", 30 * time.Second},
+		{"Fri, 28 Feb 2025 09:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q): got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
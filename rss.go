@@ -3,34 +3,76 @@ package collector
 import (
 	"encoding/xml"
 	"fmt"
+	"strconv"
 	"strings"
-	"time"
 )
 
 type rss struct {
 	Channel rssChannel `xml:"channel"`
+	// Items are the items of an RSS 1.0 document, siblings of its channel.
+	Items []rssItem `xml:"item"`
 }
 
 type rssChannel struct {
-	Items []rssItem `xml:"item"`
+	PubDate       string    `xml:"pubDate"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Date          string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Items         []rssItem `xml:"item"`
 }
 
 type rssItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	PubDate     string         `xml:"pubDate"`
+	Date        string         `xml:"http://purl.org/dc/elements/1.1/ date"`
+	GUID        rssGUID        `xml:"guid"`
+	Categories  []string       `xml:"category"`
+	Author      string         `xml:"author"`
+	Creator     string         `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Enclosures  []rssEnclosure `xml:"enclosure"`
+	Comments    string         `xml:"comments"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
 }
 
 func ParseRSS(data []byte) ([]Item, error) {
+	return Parser{}.ParseRSS(data)
+}
+
+// ParseRSS parses an RSS 2.0 or RSS 1.0 (RDF) document into items.
+// RSS 1.0 dates are read from dc:date.
+func (p Parser) ParseRSS(data []byte) ([]Item, error) {
+	data, err := toUTF8("", data)
+	if err != nil {
+		return nil, err
+	}
+
 	var feed rss
 	if err := xml.Unmarshal(data, &feed); err != nil {
 		return nil, fmt.Errorf("failed to parse RSS: %w", err)
 	}
 
-	items := make([]Item, 0, len(feed.Channel.Items))
-	for _, ri := range feed.Channel.Items {
+	feedDate := firstNonEmpty(feed.Channel.LastBuildDate, feed.Channel.PubDate, feed.Channel.Date)
+
+	rssItems := append(feed.Channel.Items, feed.Items...)
+	items := make([]Item, 0, len(rssItems))
+	for _, ri := range rssItems {
+		guid := strings.TrimSpace(ri.GUID.Value)
 		link := strings.TrimSpace(ri.Link)
+		if link == "" && ri.GUID.permaLink() {
+			link = guid
+		}
 		if link == "" {
 			continue
 		}
@@ -40,9 +82,9 @@ func ParseRSS(data []byte) ([]Item, error) {
 			title = "Untitled"
 		}
 
-		published, err := parsePubDate(ri.PubDate)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse pubDate %q: %w", ri.PubDate, err)
+		published, ok := p.itemDate(link, firstNonEmpty(ri.PubDate, ri.Date), feedDate)
+		if !ok {
+			continue
 		}
 
 		items = append(items, Item{
@@ -50,27 +92,56 @@ func ParseRSS(data []byte) ([]Item, error) {
 			Link:        link,
 			Description: ri.Description,
 			Published:   published,
+			GUID:        guid,
+			Author:      firstNonEmpty(ri.Creator, ri.Author),
+			Categories:  trimAll(ri.Categories),
+			Content:     ri.Content,
+			Enclosures:  ri.enclosures(),
+			Comments:    strings.TrimSpace(ri.Comments),
 		})
 	}
 
 	return items, nil
 }
 
-func parsePubDate(s string) (string, error) {
-	formats := []string{
-		time.RFC1123,
-		time.RFC1123Z,
-		time.RFC822,
-		time.RFC822Z,
-		time.RFC850,
-		time.RFC3339,
+// permaLink reports whether the guid is a URL that can serve as the item link.
+// Per RSS 2.0, isPermaLink defaults to true.
+func (g rssGUID) permaLink() bool {
+	if strings.TrimSpace(g.IsPermaLink) == "false" {
+		return false
+	}
+	v := strings.TrimSpace(g.Value)
+	return strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://")
+}
+
+func (ri rssItem) enclosures() []Enclosure {
+	var enclosures []Enclosure
+	for _, e := range ri.Enclosures {
+		if u := strings.TrimSpace(e.URL); u != "" {
+			length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+			enclosures = append(enclosures, Enclosure{URL: u, Type: strings.TrimSpace(e.Type), Length: length})
+		}
 	}
+	return enclosures
+}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
-			return t.UTC().Format(time.RFC3339), nil
+// firstNonEmpty returns the first of values that is not blank, trimmed.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
 		}
 	}
+	return ""
+}
 
-	return "", fmt.Errorf("unsupported date format: %s", s)
+// trimAll trims values and drops the blank ones.
+func trimAll(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
 }
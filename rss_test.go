@@ -2,6 +2,7 @@ package collector
 
 import (
 	"os"
+	"slices"
 	"testing"
 )
 
@@ -189,3 +190,128 @@ func TestParseRSS_PubDateConversion(t *testing.T) {
 		t.Errorf("items[0].Published: got %q, want %q", items[0].Published, want)
 	}
 }
+
+func TestParseRSS_GUID(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+<title>Test</title>
+<item>
+<title>Permalink GUID</title>
+<guid>https://example.com/one</guid>
+<pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate>
+</item>
+<item>
+<title>Opaque GUID</title>
+<link>https://example.com/two</link>
+<guid isPermaLink="false">tag:example.com,2025:2</guid>
+<pubDate>Fri, 28 Feb 2025 09:00:00 GMT</pubDate>
+</item>
+<item>
+<title>Opaque GUID Without Link</title>
+<guid isPermaLink="false">https://example.com/three</guid>
+<pubDate>Fri, 28 Feb 2025 08:00:00 GMT</pubDate>
+</item>
+</channel>
+</rss>`)
+
+	items, err := ParseRSS(data)
+	if err != nil {
+		t.Fatalf("ParseRSS() error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items (isPermaLink=false guid is not a link), got %d", len(items))
+	}
+	if items[0].Link != "https://example.com/one" || items[0].GUID != "https://example.com/one" {
+		t.Errorf("items[0]: permalink guid should be used as link, got %+v", items[0])
+	}
+	if items[1].GUID != "tag:example.com,2025:2" {
+		t.Errorf("items[1].GUID: got %q, want %q", items[1].GUID, "tag:example.com,2025:2")
+	}
+}
+
+func TestParseRSS_Metadata(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+<item>
+<title>Episode One</title>
+<link>https://example.com/episode-one</link>
+<author>editor@example.com (Editor)</author>
+<dc:creator>Jane Doe</dc:creator>
+<category>go</category>
+<category> podcasts </category>
+<content:encoded><![CDATA[<p>Full text.</p>]]></content:encoded>
+<enclosure url="https://example.com/one.mp3" type="audio/mpeg" length="12345"/>
+<comments>https://example.com/episode-one#comments</comments>
+<pubDate>Fri, 28 Feb 2025 09:00:00 +0000</pubDate>
+</item>
+<item>
+<title>Episode Two</title>
+<link>https://example.com/episode-two</link>
+<author>editor@example.com (Editor)</author>
+<pubDate>Fri, 28 Feb 2025 10:00:00 +0000</pubDate>
+</item>
+</channel>
+</rss>`)
+
+	items, err := ParseRSS(data)
+	if err != nil {
+		t.Fatalf("ParseRSS() error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	got := items[0]
+	if got.Author != "Jane Doe" {
+		t.Errorf("Author: got %q, want %q (dc:creator preferred)", got.Author, "Jane Doe")
+	}
+	if !slices.Equal(got.Categories, []string{"go", "podcasts"}) {
+		t.Errorf("Categories: got %q", got.Categories)
+	}
+	if got.Content != "<p>Full text.</p>" {
+		t.Errorf("Content: got %q", got.Content)
+	}
+	want := []Enclosure{{URL: "https://example.com/one.mp3", Type: "audio/mpeg", Length: 12345}}
+	if !slices.Equal(got.Enclosures, want) {
+		t.Errorf("Enclosures: got %+v, want %+v", got.Enclosures, want)
+	}
+	if got.Enclosures[0].Kind() != "audio" {
+		t.Errorf("Enclosure.Kind(): got %q, want %q", got.Enclosures[0].Kind(), "audio")
+	}
+	if got.Comments != "https://example.com/episode-one#comments" {
+		t.Errorf("Comments: got %q", got.Comments)
+	}
+
+	if items[1].Author != "editor@example.com (Editor)" {
+		t.Errorf("items[1].Author: got %q, want the author element", items[1].Author)
+	}
+}
+
+func TestParseRSS_RDF(t *testing.T) {
+	data, err := os.ReadFile("testdata/rdf.xml")
+	if err != nil {
+		t.Fatalf("cannot read fixture: %v", err)
+	}
+
+	items, err := ParseFeed(data)
+	if err != nil {
+		t.Fatalf("ParseFeed() error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	got := items[0]
+	if got.Title != "RDF One" || got.Link != "https://example.net/rdf-one" || got.Description != "First RDF item." || got.Author != "Jane Doe" {
+		t.Errorf("items[0]: unexpected %+v", got)
+	}
+	if got.Published != "2025-02-28T09:00:00Z" {
+		t.Errorf("items[0].Published: got %q, want %q (from dc:date)", got.Published, "2025-02-28T09:00:00Z")
+	}
+	if items[1].Title != "RDF Two" {
+		t.Errorf("items[1].Title: got %q", items[1].Title)
+	}
+}
@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"context"
+	"sync"
+)
+
+// Source yields items directly, e.g. from an API, rather than as a feed
+// document to fetch and parse. Sources are collected alongside the feeds
+// passed to UpdateFeeds; see WithSources.
+type Source interface {
+	// Name is recorded as Item.Source and identifies the source in errors.
+	Name() string
+	Items(ctx context.Context) ([]Item, error)
+}
+
+// fetchSources collects items from c.sources with at most c.concurrency
+// sources queried at the same time. Results are returned in source order.
+func (c *Collector) fetchSources(ctx context.Context) []feedResult {
+	results := make([]feedResult, len(c.sources))
+	sem := make(chan struct{}, max(c.concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, src := range c.sources {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = feedResult{feed: Feed{Name: src.Name()}}
+			results[i].items, results[i].err = src.Items(ctx)
+		})
+	}
+	wg.Wait()
+
+	return results
+}
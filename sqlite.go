@@ -0,0 +1,186 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS meta (
+	id   INTEGER PRIMARY KEY CHECK (id = 1),
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS items (
+	link      TEXT PRIMARY KEY,
+	published TEXT NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS items_published ON items (published);
+`
+
+// SQLiteStore keeps the collection in an SQLite database.
+// Items are stored as JSON documents keyed by link and indexed by publish date,
+// so appending does not rewrite existing rows. Only Replace, used by an
+// explicit Collector.Write, rewrites the table.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens or creates the database at path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open database %q: %w", path, err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot initialize database %q: %w", path, err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Load(ctx context.Context) (Meta, []Item, error) {
+	var meta Meta
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM meta WHERE id = 1`).Scan(&data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return Meta{}, nil, fmt.Errorf("cannot load metadata: %w", err)
+	default:
+		if err := json.Unmarshal([]byte(data), &meta); err != nil {
+			return Meta{}, nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+	}
+
+	items, err := s.query(ctx, `SELECT data FROM items ORDER BY published, rowid`)
+	if err != nil {
+		return Meta{}, nil, err
+	}
+
+	return meta, items, nil
+}
+
+func (s *SQLiteStore) Append(ctx context.Context, meta Meta, items []Item) error {
+	return s.save(ctx, meta, items, false)
+}
+
+// Replace deletes the stored items and saves items in their place,
+// in a single transaction.
+func (s *SQLiteStore) Replace(ctx context.Context, meta Meta, items []Item) error {
+	return s.save(ctx, meta, items, true)
+}
+
+func (s *SQLiteStore) save(ctx context.Context, meta Meta, items []Item, replace bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if replace {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM items`); err != nil {
+			return fmt.Errorf("cannot delete items: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO meta (id, data) VALUES (1, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+		string(data)); err != nil {
+		return fmt.Errorf("cannot save metadata: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO items (link, published, data) VALUES (?, ?, ?)
+		ON CONFLICT (link) DO UPDATE SET published = excluded.published, data = excluded.data`)
+	if err != nil {
+		return fmt.Errorf("cannot prepare insert: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, item := range items {
+		if item.Link == "" {
+			return fmt.Errorf("cannot store item with empty link: %q", item.Title)
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, item.Link, item.Published, string(data)); err != nil {
+			return fmt.Errorf("cannot store item %q: %w", item.Link, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStore) Range(ctx context.Context, from, to time.Time) ([]Item, error) {
+	return s.query(ctx,
+		`SELECT data FROM items WHERE published >= ? AND published < ? ORDER BY published, rowid`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+}
+
+func (s *SQLiteStore) Lookup(ctx context.Context, link string) (Item, bool, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM items WHERE link = ?`, link).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Item{}, false, nil
+	}
+	if err != nil {
+		return Item{}, false, fmt.Errorf("cannot look up %q: %w", link, err)
+	}
+
+	var item Item
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		return Item{}, false, fmt.Errorf("invalid item %q: %w", link, err)
+	}
+	return item, true, nil
+}
+
+func (s *SQLiteStore) query(ctx context.Context, query string, args ...any) ([]Item, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query items: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []Item
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("cannot read item: %w", err)
+		}
+		var item Item
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("invalid item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot query items: %w", err)
+	}
+
+	return items, nil
+}
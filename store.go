@@ -0,0 +1,244 @@
+package collector
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+)
+
+// Store persists a collection of items.
+type Store interface {
+	// Load returns the collection metadata and all stored items.
+	// A store that does not exist yet is empty, not an error.
+	Load(ctx context.Context) (Meta, []Item, error)
+	// Append saves meta and adds items, replacing stored items with the same link.
+	Append(ctx context.Context, meta Meta, items []Item) error
+	// Range returns the items published in [from, to), ordered by publish date.
+	Range(ctx context.Context, from, to time.Time) ([]Item, error)
+	// Lookup returns the item stored under link.
+	Lookup(ctx context.Context, link string) (Item, bool, error)
+}
+
+// Replacer is implemented by stores that can replace their whole content.
+// Collector.Write uses it to save edits and removals made to Collector.Items;
+// stores without it only get the new and changed items appended.
+type Replacer interface {
+	// Replace saves meta and items as the complete collection.
+	Replace(ctx context.Context, meta Meta, items []Item) error
+}
+
+// Meta is the collection-level data kept by a Store.
+type Meta struct {
+	Title   string               `json:"title"`
+	Updated string               `json:"updated"`
+	Feeds   map[string]FeedState `json:"feeds,omitempty"`
+}
+
+// JSONStore keeps the whole collection in one indented JSON file,
+// which is atomically rewritten on every Append.
+type JSONStore struct {
+	fileName string
+	loaded   bool
+	meta     Meta
+	itemIndex
+}
+
+// itemIndex is an in-memory list of items with a lookup by link.
+type itemIndex struct {
+	items []Item
+	index map[string]int
+}
+
+type jsonDocument struct {
+	Title   string               `json:"title"`
+	Updated string               `json:"updated"`
+	Feeds   map[string]FeedState `json:"feeds,omitempty"`
+	Items   []Item               `json:"items"`
+}
+
+func NewJSONStore(fileName string) *JSONStore {
+	return &JSONStore{fileName: fileName}
+}
+
+func (s *JSONStore) Load(ctx context.Context) (Meta, []Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Meta{}, nil, err
+	}
+
+	s.loaded = false
+	s.meta = Meta{}
+	s.itemIndex = newItemIndex()
+
+	if _, err := os.Stat(s.fileName); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.loaded = true
+			return Meta{}, nil, nil
+		}
+		return Meta{}, nil, fmt.Errorf("cannot stat file %q: %w", s.fileName, err)
+	}
+
+	data, err := os.ReadFile(s.fileName)
+	if err != nil {
+		return Meta{}, nil, fmt.Errorf("cannot read file %q: %w", s.fileName, err)
+	}
+
+	var doc jsonDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Meta{}, nil, fmt.Errorf("invalid JSON in %q: %w", s.fileName, err)
+	}
+
+	for _, item := range doc.Items {
+		if item.Link == "" {
+			log.Printf("skipping item with empty link: %q", item.Title)
+			continue
+		}
+		s.put(item)
+	}
+	s.meta = Meta{Title: doc.Title, Updated: doc.Updated, Feeds: doc.Feeds}
+	s.loaded = true
+
+	return s.meta, slices.Clone(s.items), nil
+}
+
+// Append rewrites the file with the added items, sorted by publish date.
+func (s *JSONStore) Append(ctx context.Context, meta Meta, items []Item) error {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		s.put(item)
+	}
+	return s.save(ctx, meta)
+}
+
+// Replace rewrites the file with exactly items, sorted by publish date.
+func (s *JSONStore) Replace(ctx context.Context, meta Meta, items []Item) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.itemIndex = newItemIndex()
+	for _, item := range items {
+		s.put(item)
+	}
+	s.loaded = true
+	return s.save(ctx, meta)
+}
+
+func (s *JSONStore) save(ctx context.Context, meta Meta) error {
+	s.sort()
+	s.meta = meta
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "    ")
+	enc.SetEscapeHTML(false)
+
+	doc := jsonDocument{Title: meta.Title, Updated: meta.Updated, Feeds: meta.Feeds, Items: s.items}
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(ctx, s.fileName, buf.Bytes())
+}
+
+func (s *JSONStore) Range(ctx context.Context, from, to time.Time) ([]Item, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	return s.rangeItems(from, to), nil
+}
+
+func (s *JSONStore) Lookup(ctx context.Context, link string) (Item, bool, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return Item{}, false, err
+	}
+	item, ok := s.lookup(link)
+	return item, ok, nil
+}
+
+func (s *JSONStore) ensureLoaded(ctx context.Context) error {
+	if s.loaded {
+		return ctx.Err()
+	}
+	_, _, err := s.Load(ctx)
+	return err
+}
+
+// writeFileAtomic replaces fileName with data through a temp file and rename.
+// The file is left untouched if ctx is done before the rename.
+func writeFileAtomic(ctx context.Context, fileName string, data []byte) error {
+	tmp := fileName + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("cannot write temp file %q: %w", tmp, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, fileName); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("cannot rename %q to %q: %w", tmp, fileName, err)
+	}
+
+	return nil
+}
+
+func newItemIndex() itemIndex {
+	return itemIndex{index: make(map[string]int)}
+}
+
+// put adds item, replacing the item with the same link in place.
+func (x *itemIndex) put(item Item) {
+	if i, ok := x.index[item.Link]; ok {
+		x.items[i] = item
+		return
+	}
+	x.index[item.Link] = len(x.items)
+	x.items = append(x.items, item)
+}
+
+func (x *itemIndex) lookup(link string) (Item, bool) {
+	i, ok := x.index[link]
+	if !ok {
+		return Item{}, false
+	}
+	return x.items[i], true
+}
+
+// sort orders items by publish date, keeping insertion order for equal dates.
+func (x *itemIndex) sort() {
+	slices.SortStableFunc(x.items, func(a, b Item) int {
+		return cmp.Compare(a.Published, b.Published)
+	})
+	for i, item := range x.items {
+		x.index[item.Link] = i
+	}
+}
+
+// rangeItems returns the items published in [from, to), ordered by publish date.
+func (x *itemIndex) rangeItems(from, to time.Time) []Item {
+	lo := from.UTC().Format(time.RFC3339)
+	hi := to.UTC().Format(time.RFC3339)
+
+	var found []Item
+	for _, item := range x.items {
+		if item.Published >= lo && item.Published < hi {
+			found = append(found, item)
+		}
+	}
+	slices.SortStableFunc(found, func(a, b Item) int {
+		return cmp.Compare(a.Published, b.Published)
+	})
+	return found
+}
@@ -0,0 +1,240 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testStores(t *testing.T) map[string]func(t *testing.T) Store {
+	t.Helper()
+	return map[string]func(t *testing.T) Store{
+		"json": func(t *testing.T) Store {
+			return NewJSONStore(filepath.Join(t.TempDir(), "data.json"))
+		},
+		"jsonl": func(t *testing.T) Store {
+			return NewJSONLStore(filepath.Join(t.TempDir(), "data.jsonl"))
+		},
+		"sqlite": func(t *testing.T) Store {
+			s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "data.db"))
+			if err != nil {
+				t.Fatalf("NewSQLiteStore() error: %v", err)
+			}
+			t.Cleanup(func() { _ = s.Close() })
+			return s
+		},
+	}
+}
+
+func TestStore_AppendLoadQuery(t *testing.T) {
+	ctx := context.Background()
+
+	for name, newStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+
+			meta, items, err := s.Load(ctx)
+			if err != nil {
+				t.Fatalf("Load() on empty store error: %v", err)
+			}
+			if meta.Title != "" || len(items) != 0 {
+				t.Fatalf("expected empty store, got %+v, %d items", meta, len(items))
+			}
+
+			meta = Meta{
+				Title:   "Test",
+				Updated: "2025-03-01T00:00:00Z",
+				Feeds:   map[string]FeedState{"abc": {ETag: `"v1"`}},
+			}
+			err = s.Append(ctx, meta, []Item{
+				{Title: "Two", Link: "https://example.com/two", Published: "2025-02-28T10:00:00Z"},
+				{Title: "One", Link: "https://example.com/one", Published: "2025-02-27T10:00:00Z"},
+			})
+			if err != nil {
+				t.Fatalf("Append() error: %v", err)
+			}
+
+			err = s.Append(ctx, meta, []Item{
+				{Title: "Three", Link: "https://example.com/three", Published: "2025-03-01T10:00:00Z"},
+				{Title: "Two (fixed)", Link: "https://example.com/two", Published: "2025-02-28T10:00:00Z"},
+			})
+			if err != nil {
+				t.Fatalf("second Append() error: %v", err)
+			}
+
+			gotMeta, items, err := s.Load(ctx)
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if gotMeta.Title != "Test" || gotMeta.Feeds["abc"].ETag != `"v1"` {
+				t.Errorf("unexpected meta: %+v", gotMeta)
+			}
+			if len(items) != 3 {
+				t.Fatalf("expected 3 items, got %d", len(items))
+			}
+			if items[0].Title != "One" || items[1].Title != "Two (fixed)" {
+				t.Errorf("expected items sorted by date with replaced item, got %q, %q", items[0].Title, items[1].Title)
+			}
+
+			from := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+			to := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+			found, err := s.Range(ctx, from, to)
+			if err != nil {
+				t.Fatalf("Range() error: %v", err)
+			}
+			if len(found) != 1 || found[0].Link != "https://example.com/two" {
+				t.Errorf("Range(): unexpected items %+v", found)
+			}
+
+			item, ok, err := s.Lookup(ctx, "https://example.com/three")
+			if err != nil || !ok || item.Title != "Three" {
+				t.Errorf("Lookup(): got %+v, %v, %v", item, ok, err)
+			}
+			if _, ok, _ := s.Lookup(ctx, "https://example.com/missing"); ok {
+				t.Error("Lookup() should not find missing link")
+			}
+		})
+	}
+}
+
+func TestUpdate_WithSQLiteStore(t *testing.T) {
+	feedData := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>One</title><link>https://example.com/one</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	path := filepath.Join(t.TempDir(), "data.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error: %v", err)
+	}
+
+	c := New(path, WithStore(s), WithFetcher(&stubFetcher{body: feedData}))
+	if _, err := c.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	s2, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error: %v", err)
+	}
+	c2 := New(path, WithStore(s2))
+	defer func() { _ = c2.Close() }()
+
+	if err := c2.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(c2.Items) != 1 || c2.Updated == "" {
+		t.Errorf("expected 1 item and Updated set, got %d items, Updated %q", len(c2.Items), c2.Updated)
+	}
+}
+
+func TestWrite_KeepsEditsAndRemovals(t *testing.T) {
+	for name, newStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			if _, ok := s.(Replacer); !ok {
+				t.Skip("append-only store")
+			}
+
+			c := New("", WithStore(s))
+			c.Items = []Item{
+				{Title: "A", Link: "https://example.com/a", Published: "2025-02-27T10:00:00Z"},
+				{Title: "B", Link: "https://example.com/b", Published: "2025-02-28T10:00:00Z"},
+			}
+			if err := c.Write(); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+
+			if err := c.Read(); err != nil {
+				t.Fatalf("Read() error: %v", err)
+			}
+			a := c.Items[0]
+			a.Title = "A (edited)"
+			c.Items = []Item{a}
+			if err := c.Write(); err != nil {
+				t.Fatalf("second Write() error: %v", err)
+			}
+
+			_, items, err := s.Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if len(items) != 1 || items[0].Title != "A (edited)" {
+				t.Errorf("expected only the edited item, got %+v", items)
+			}
+		})
+	}
+}
+
+// replaceCounter counts the calls to Replace of the wrapped store.
+type replaceCounter struct {
+	*JSONStore
+	replaced int
+}
+
+func (s *replaceCounter) Replace(ctx context.Context, meta Meta, items []Item) error {
+	s.replaced++
+	return s.JSONStore.Replace(ctx, meta, items)
+}
+
+func TestUpdate_AppendsWithoutReplacing(t *testing.T) {
+	feedData := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>One</title><link>https://example.com/one</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	s := &replaceCounter{JSONStore: NewJSONStore(path)}
+	c := New(path, WithStore(s), WithFetcher(&stubFetcher{body: feedData}))
+
+	if _, err := c.Update("stub://feed"); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if _, err := c.Import([]Item{{Title: "Two", Link: "https://example.com/two", Published: "2025-02-27T10:00:00Z"}}); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if s.replaced != 0 {
+		t.Errorf("Update() and Import() should append, got %d Replace calls", s.replaced)
+	}
+
+	if err := c.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if s.replaced != 1 {
+		t.Errorf("Write() should replace the stored items, got %d Replace calls", s.replaced)
+	}
+}
+
+// failingStore fails every Append of the wrapped store.
+type failingStore struct {
+	*JSONStore
+}
+
+func (s failingStore) Append(context.Context, Meta, []Item) error {
+	return errors.New("disk full")
+}
+
+func TestUpdate_WriteError(t *testing.T) {
+	feedData := []byte(`<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>One</title><link>https://example.com/one</link><pubDate>Fri, 28 Feb 2025 10:00:00 GMT</pubDate></item>
+</channel></rss>`)
+
+	path := filepath.Join(t.TempDir(), "data.json")
+	c := New(path, WithStore(failingStore{NewJSONStore(path)}), WithFetcher(&stubFetcher{body: feedData}))
+
+	updated, err := c.Update("stub://feed")
+	if err == nil {
+		t.Fatal("Update() should return the store error")
+	}
+	if updated {
+		t.Error("Update() should not report an update that was not written")
+	}
+	var feedErr *FeedError
+	if errors.As(err, &feedErr) {
+		t.Errorf("the store error should not be a *FeedError: %v", err)
+	}
+}
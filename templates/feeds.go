@@ -0,0 +1,306 @@
+package templates
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+const (
+	// maxFeedItems is the number of latest links in the links feeds.
+	maxFeedItems = 50
+	// maxFeedWeeks is the number of latest weeks in the weekly feeds.
+	maxFeedWeeks = 20
+)
+
+// weekHTML renders the content of a weekly feed entry. Descriptions, often
+// HTML themselves, are reduced to text.
+var weekHTML = template.Must(template.New("week").Funcs(template.FuncMap{"plainText": plainText}).Parse(`<ul>
+{{- range . }}
+<li><a href="{{ .Link }}">{{ .Title }}</a>{{ with plainText .Description }} — {{ . }}{{ end }}</li>
+{{- end }}
+</ul>`))
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       *atomLink      `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// feedEntry is a format-neutral entry of the links or weekly feed.
+type feedEntry struct {
+	Title      string
+	ID         string
+	Link       string
+	Updated    time.Time
+	Published  time.Time
+	Author     string
+	Categories []string
+	Summary    string
+	// HTML is the entry content; Summary is used when empty.
+	HTML string
+}
+
+// feedDoc is a format-neutral feed written as links.atom and links.rss,
+// or weeks.atom and weeks.rss.
+type feedDoc struct {
+	Name    string
+	Title   string
+	Entries []feedEntry
+}
+
+// writeFeeds writes the Atom feeds of the latest links and of the weekly
+// digests into cfg.BaseDir, and their RSS 2.0 versions when cfg.RSS is set.
+func writeFeeds(ctx context.Context, s *collector.Collector, weeks []Week, cfg Config) error {
+	title := cmp.Or(s.Title, "Links")
+	docs := []feedDoc{
+		{Name: "links", Title: title, Entries: linkEntries(s.Items, cfg)},
+		{Name: "weeks", Title: title + " — weekly", Entries: weekEntries(weeks, cfg)},
+	}
+
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writeXML(filepath.Join(cfg.BaseDir, doc.Name+".atom"), doc.atom(cfg)); err != nil {
+			return err
+		}
+		if cfg.RSS {
+			if err := writeXML(filepath.Join(cfg.BaseDir, doc.Name+".rss"), doc.rss(cfg)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// linkEntries returns the latest collected links, newest first.
+func linkEntries(items []collector.Item, cfg Config) []feedEntry {
+	items = slices.Clone(items)
+	slices.SortStableFunc(items, func(a, b collector.Item) int {
+		return cmp.Compare(itemTime(b, cfg.GroupBy), itemTime(a, cfg.GroupBy))
+	})
+	items = items[:min(len(items), maxFeedItems)]
+
+	entries := make([]feedEntry, 0, len(items))
+	for _, item := range items {
+		published, _ := time.Parse(time.RFC3339, itemTime(item, cfg.GroupBy))
+		updated := published
+		if modified, err := time.Parse(time.RFC3339, item.Modified); err == nil {
+			updated = modified
+		}
+
+		entries = append(entries, feedEntry{
+			Title:      item.Title,
+			ID:         item.Link,
+			Link:       item.Link,
+			Updated:    updated,
+			Published:  published,
+			Author:     item.Author,
+			Categories: item.Categories,
+			Summary:    plainText(item.Description),
+		})
+	}
+	return entries
+}
+
+// weekEntries returns one entry per week listing its links, newest week first.
+func weekEntries(weeks []Week, cfg Config) []feedEntry {
+	var entries []feedEntry
+	for i := len(weeks) - 1; i >= 0 && len(entries) < maxFeedWeeks; i-- {
+		week := weeks[i]
+
+		var updated time.Time
+		for _, item := range week.Items {
+			if t, err := time.Parse(time.RFC3339, itemTime(item, cfg.GroupBy)); err == nil && t.After(updated) {
+				updated = t
+			}
+		}
+
+		var content strings.Builder
+		_ = weekHTML.Execute(&content, week.Items)
+
+		link := siteURL(cfg, "data/"+week.Name+".md")
+		entries = append(entries, feedEntry{
+			Title:   fmt.Sprintf("Week %s (%d links)", week.Name, len(week.Items)),
+			ID:      cmp.Or(link, "urn:instapaper-collector:week:"+week.Name),
+			Link:    link,
+			Updated: updated,
+			HTML:    content.String(),
+		})
+	}
+	return entries
+}
+
+func (d feedDoc) atom(cfg Config) atomFeed {
+	feed := atomFeed{
+		Title:  d.Title,
+		ID:     cmp.Or(siteURL(cfg, d.Name+".atom"), "urn:instapaper-collector:"+d.Name),
+		Author: atomPerson{Name: cmp.Or(cfg.UserName, d.Title)},
+	}
+	if cfg.SiteURL != "" {
+		feed.Links = []atomLink{
+			{Href: siteURL(cfg, d.Name+".atom"), Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL(cfg, "")},
+		}
+	}
+
+	var updated time.Time
+	for _, e := range d.Entries {
+		entry := atomEntry{
+			Title:   e.Title,
+			ID:      e.ID,
+			Updated: atomTime(e.Updated),
+			Summary: e.Summary,
+		}
+		if e.Link != "" {
+			entry.Link = &atomLink{Href: e.Link}
+		}
+		if !e.Published.IsZero() {
+			entry.Published = atomTime(e.Published)
+		}
+		if e.Author != "" {
+			entry.Author = &atomPerson{Name: e.Author}
+		}
+		for _, c := range e.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+		if e.HTML != "" {
+			entry.Content = &atomContent{Type: "html", Body: e.HTML}
+		}
+		feed.Entries = append(feed.Entries, entry)
+
+		if e.Updated.After(updated) {
+			updated = e.Updated
+		}
+	}
+	feed.Updated = atomTime(updated)
+
+	return feed
+}
+
+func (d feedDoc) rss(cfg Config) rssFeed {
+	channel := rssChannel{
+		Title:       d.Title,
+		Link:        cmp.Or(siteURL(cfg, ""), "https://github.com/"+cfg.UserName),
+		Description: d.Title,
+	}
+
+	var updated time.Time
+	for _, e := range d.Entries {
+		item := rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{Value: e.ID, IsPermaLink: e.ID == e.Link},
+			PubDate:     cmp.Or(e.Published, e.Updated).UTC().Format(time.RFC1123Z),
+			Categories:  e.Categories,
+			Description: cmp.Or(e.HTML, e.Summary),
+		}
+		channel.Items = append(channel.Items, item)
+
+		if e.Updated.After(updated) {
+			updated = e.Updated
+		}
+	}
+	channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+
+	return rssFeed{Version: "2.0", Channel: channel}
+}
+
+// siteURL resolves path against cfg.SiteURL; "" when no site URL is configured.
+func siteURL(cfg Config, path string) string {
+	if cfg.SiteURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(cfg.SiteURL, "/") + "/" + path
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func writeXML(fileName string, v any) error {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0770); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, data, 0644)
+}
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+func feedTestCollector() *collector.Collector {
+	return &collector.Collector{
+		Title: "Links",
+		Items: []collector.Item{
+			{Title: "Week 9 <Article>", Link: "https://example.com/w9", Description: "Old & gold", Published: "2025-02-24T10:00:00Z"},
+			{Title: "Week 10 One", Link: "https://example.com/w10-1", Published: "2025-03-03T10:00:00Z", Categories: []string{"go"}},
+			{Title: "Week 10 Two", Link: "https://example.com/w10-2", Published: "2025-03-04T10:00:00Z", Author: "Jane Doe"},
+		},
+	}
+}
+
+func TestRender_Feeds(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{UserName: "juev", BaseDir: dir, SiteURL: "https://github.com/juev/links/blob/main/", Feeds: true, RSS: true}
+
+	if err := Render(context.Background(), feedTestCollector(), cfg); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	links, err := os.ReadFile(filepath.Join(dir, "links.atom"))
+	if err != nil {
+		t.Fatalf("links.atom not created: %v", err)
+	}
+	items, err := collector.ParseAtom(links)
+	if err != nil {
+		t.Fatalf("links.atom is not a valid feed: %v", err)
+	}
+	if len(items) != 3 || items[0].Link != "https://example.com/w10-2" {
+		t.Fatalf("links.atom: expected 3 items, newest first, got %+v", items)
+	}
+	if items[0].Author != "Jane Doe" || items[1].Categories[0] != "go" || items[2].Description != "Old & gold" {
+		t.Errorf("links.atom: item metadata not written: %+v", items)
+	}
+
+	weeks, err := os.ReadFile(filepath.Join(dir, "weeks.atom"))
+	if err != nil {
+		t.Fatalf("weeks.atom not created: %v", err)
+	}
+	entries, err := collector.ParseAtom(weeks)
+	if err != nil {
+		t.Fatalf("weeks.atom is not a valid feed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("weeks.atom: expected 2 weekly entries, got %d", len(entries))
+	}
+	if entries[0].Title != "Week 2025-10 (2 links)" || entries[0].Link != "https://github.com/juev/links/blob/main/data/2025-10.md" {
+		t.Errorf("weeks.atom: unexpected first entry %+v", entries[0])
+	}
+	if !strings.Contains(entries[1].Description, `<a href="https://example.com/w9">Week 9 &lt;Article&gt;</a> — Old &amp; gold`) {
+		t.Errorf("weeks.atom: week content should list the escaped links, got %q", entries[1].Description)
+	}
+
+	for _, name := range []string{"links.rss", "weeks.rss"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s not created: %v", name, err)
+		}
+		items, err := collector.ParseRSS(data)
+		if err != nil {
+			t.Fatalf("%s is not a valid feed: %v", name, err)
+		}
+		if len(items) == 0 {
+			t.Errorf("%s has no items", name)
+		}
+	}
+}
+
+func TestRender_FeedsWithoutSiteURL(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Render(context.Background(), feedTestCollector(), Config{UserName: "juev", BaseDir: dir, Feeds: true}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "links.rss")); !os.IsNotExist(err) {
+		t.Error("links.rss should only be written with RSS enabled")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "weeks.atom"))
+	if err != nil {
+		t.Fatalf("weeks.atom not created: %v", err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("weeks.atom is not valid XML: %v", err)
+	}
+	if len(feed.Entries) != 2 || feed.Entries[0].ID != "urn:instapaper-collector:week:2025-10" || feed.Entries[0].Link != nil {
+		t.Errorf("weeks.atom: unexpected entries %+v", feed.Entries)
+	}
+	if feed.Updated != "2025-03-04T10:00:00Z" {
+		t.Errorf("weeks.atom: updated got %q, want the latest item time", feed.Updated)
+	}
+}
+
+func TestRender_NoFeedsByDefault(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Render(context.Background(), feedTestCollector(), Config{UserName: "juev", BaseDir: dir, RSS: true}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	for _, name := range []string{"links.atom", "weeks.atom", "links.rss", "weeks.rss"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should only be written with Feeds enabled", name)
+		}
+	}
+}
+
+func TestRender_FeedsHTMLDescription(t *testing.T) {
+	dir := t.TempDir()
+	c := &collector.Collector{Items: []collector.Item{
+		{Title: "One", Link: "https://example.com/one", Description: "<p>Hello <b>world</b> &amp; co</p>", Published: "2025-03-03T10:00:00Z"},
+	}}
+
+	if err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir, SiteURL: "https://example.com/links", Feeds: true, RSS: true}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	for _, name := range []string{"links.atom", "links.rss", "weeks.atom", "weeks.rss"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s not created: %v", name, err)
+		}
+		items, err := collector.ParseFeed(data)
+		if err != nil {
+			t.Fatalf("%s is not a valid feed: %v", name, err)
+		}
+		if len(items) != 1 || !strings.Contains(items[0].Description, "Hello world &") {
+			t.Errorf("%s: description should be the text of the HTML, got %+v", name, items)
+		}
+		if strings.Contains(string(data), "&lt;p&gt;") || strings.Contains(string(data), "&amp;lt;") {
+			t.Errorf("%s: description markup should not be escaped into the feed:\n%s", name, data)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package templates
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	collector "github.com/juev/instapaper-collector"
+	"github.com/juev/instapaper-collector/internal/htmltext"
+)
+
+// wordsPerMinute is the reading speed assumed by readingTime.
+const wordsPerMinute = 200
+
+// Group is a set of items sharing a key, as returned by the groupBy function.
+type Group struct {
+	Key   string
+	Items []collector.Item
+}
+
+// Funcs returns the functions available to the Markdown and HTML templates:
+//
+//	domain LINK              host of a link without "www."
+//	date LAYOUT TIME         format an RFC 3339 time in cfg.Location, e.g. {{ .Published | date "2 Jan 2006" }}
+//	relative TIME            "3 days ago", "just now", "in 2 hours"
+//	truncate N TEXT          shorten to N characters, ending with "…"
+//	escapeMarkdown TEXT      escape characters with a meaning in Markdown, on one line
+//	plainText HTML           the text of an HTML fragment, on one line
+//	markdownText HTML        escapeMarkdown of the text of an HTML fragment
+//	markdownURL LINK         a link target safe inside (…)
+//	markdownCode TEXT        an inline code span
+//	readingTime ITEM|TEXT    estimated minutes to read the content or description
+//	groupBy KEY ITEMS        []Group by "domain", "tag", "source", "author" or "weekday"
+//	sortBy KEY ITEMS         items sorted by "published", "collected", "title", "domain" or "source"; "-published" sorts descending
+//	join SEP LIST            strings.Join
+func Funcs(cfg Config) map[string]any {
+	return funcs(cfg, time.Now)
+}
+
+func funcs(cfg Config, now func() time.Time) map[string]any {
+	loc := cmp.Or(cfg.Location, time.UTC)
+
+	return map[string]any{
+		"domain": domain,
+		"date": func(layout string, value any) (string, error) {
+			t, err := toTime(value)
+			if err != nil || t.IsZero() {
+				return "", err
+			}
+			return t.In(loc).Format(layout), nil
+		},
+		"relative": func(value any) (string, error) {
+			t, err := toTime(value)
+			if err != nil || t.IsZero() {
+				return "", err
+			}
+			return relative(t, now()), nil
+		},
+		"truncate":       truncate,
+		"escapeMarkdown": escapeMarkdown,
+		"plainText":      plainText,
+		"markdownText":   markdownText,
+		"markdownURL":    markdownURL,
+		"markdownCode":   markdownCode,
+		"readingTime":    readingTime,
+		"groupBy": func(key string, items []collector.Item) ([]Group, error) {
+			return groupBy(key, items, cfg.GroupBy, loc)
+		},
+		"sortBy": func(key string, items []collector.Item) ([]collector.Item, error) {
+			return sortBy(key, items)
+		},
+		"join": func(sep string, list []string) string {
+			return strings.Join(list, sep)
+		},
+	}
+}
+
+// domain returns the host of link without a "www." prefix, or "" if link is not a URL.
+func domain(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// toTime accepts a time.Time or an RFC 3339 string; "" yields the zero time.
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if v == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, v)
+	default:
+		return time.Time{}, fmt.Errorf("cannot use %T as a time", value)
+	}
+}
+
+// relative describes t relative to now in the largest whole unit.
+func relative(t, now time.Time) string {
+	d := now.Sub(t)
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, ""
+	}
+
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	case d < 7*24*time.Hour:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d < 30*24*time.Hour:
+		n, unit = int(d/(7*24*time.Hour)), "week"
+	case d < 365*24*time.Hour:
+		n, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		n, unit = int(d/(365*24*time.Hour)), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+
+	if suffix == "" {
+		return fmt.Sprintf("in %d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s %s", n, unit, suffix)
+}
+
+// truncate shortens s to at most n characters, cutting at a word boundary
+// when there is one and marking the cut with "…".
+func truncate(n int, s string) string {
+	s = strings.TrimSpace(s)
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	cut := string(runes[:n-1])
+	if i := strings.LastIndexAny(cut, " \t\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " \t\n.,;:") + "…"
+}
+
+// readingTime estimates the minutes needed to read an item's content,
+// falling back to its description, or a text. It is at least 1.
+func readingTime(value any) (int, error) {
+	var text string
+	switch v := value.(type) {
+	case collector.Item:
+		text = cmp.Or(v.Content, v.Description)
+	case string:
+		text = v
+	default:
+		return 0, fmt.Errorf("cannot estimate reading time of %T", value)
+	}
+
+	words := len(strings.Fields(htmltext.Text(text)))
+	return max(1, int(math.Ceil(float64(words)/wordsPerMinute))), nil
+}
+
+// groupBy groups items by key. Groups are sorted by key, weekdays from
+// Monday; an item with several tags appears in each of their groups, and
+// items without a value for the key are grouped under "".
+func groupBy(key string, items []collector.Item, by GroupBy, loc *time.Location) ([]Group, error) {
+	var keysOf func(collector.Item) []string
+	switch key {
+	case "domain":
+		keysOf = func(item collector.Item) []string { return []string{domain(item.Link)} }
+	case "tag", "category":
+		keysOf = func(item collector.Item) []string {
+			if len(item.Categories) == 0 {
+				return []string{""}
+			}
+			return item.Categories
+		}
+	case "source":
+		keysOf = func(item collector.Item) []string { return []string{item.Source} }
+	case "author":
+		keysOf = func(item collector.Item) []string { return []string{item.Author} }
+	case "weekday":
+		keysOf = func(item collector.Item) []string {
+			t, err := time.Parse(time.RFC3339, itemTime(item, by))
+			if err != nil {
+				return []string{""}
+			}
+			return []string{t.In(loc).Weekday().String()}
+		}
+	default:
+		return nil, fmt.Errorf("cannot group by %q: want domain, tag, source, author or weekday", key)
+	}
+
+	var groups []Group
+	index := make(map[string]int)
+	for _, item := range items {
+		for _, k := range keysOf(item) {
+			i, ok := index[k]
+			if !ok {
+				i = len(groups)
+				index[k] = i
+				groups = append(groups, Group{Key: k})
+			}
+			groups[i].Items = append(groups[i].Items, item)
+		}
+	}
+
+	order := func(k string) string { return k }
+	if key == "weekday" {
+		// Monday first, as in ISO weeks.
+		order = func(k string) string {
+			for d := range 7 {
+				if time.Weekday((d+1)%7).String() == k {
+					return fmt.Sprint(d)
+				}
+			}
+			return k
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b Group) int {
+		return cmp.Compare(order(a.Key), order(b.Key))
+	})
+
+	return groups, nil
+}
+
+// sortBy returns a sorted copy of items. A "-" prefix sorts in descending order.
+func sortBy(key string, items []collector.Item) ([]collector.Item, error) {
+	field, desc := strings.CutPrefix(key, "-")
+
+	var value func(collector.Item) string
+	switch field {
+	case "published":
+		value = func(item collector.Item) string { return item.Published }
+	case "collected":
+		value = func(item collector.Item) string { return cmp.Or(item.Collected, item.Published) }
+	case "title":
+		value = func(item collector.Item) string { return strings.ToLower(item.Title) }
+	case "domain":
+		value = func(item collector.Item) string { return domain(item.Link) }
+	case "source":
+		value = func(item collector.Item) string { return item.Source }
+	default:
+		return nil, fmt.Errorf("cannot sort by %q: want published, collected, title, domain or source", key)
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b collector.Item) int {
+		if desc {
+			return cmp.Compare(value(b), value(a))
+		}
+		return cmp.Compare(value(a), value(b))
+	})
+	return sorted, nil
+}
@@ -0,0 +1,73 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/juev/instapaper-collector/internal/htmltext"
+)
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+		"<", `\<`, ">", `\>`, "|", `\|`, "~", `\~`, "$", `\$`,
+	)
+	// markdownEntity matches what Markdown would decode as an HTML entity.
+	markdownEntity = regexp.MustCompile(`&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+	// markdownBlock matches text that would start a heading, list, rule or
+	// setext underline at the beginning of a line.
+	markdownBlock = regexp.MustCompile(`^([#+=-]|[0-9]+[.)])`)
+)
+
+// escapeMarkdown makes s render literally inline: whitespace, including
+// newlines, is collapsed to single spaces and the characters that start
+// emphasis, code, links, tables, math, HTML or entities are escaped, as is a
+// leading heading or list marker.
+func escapeMarkdown(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = markdownEscaper.Replace(s)
+	s = markdownEntity.ReplaceAllString(s, `\$0`)
+	if m := markdownBlock.FindString(s); m != "" {
+		s = m[:len(m)-1] + `\` + s[len(m)-1:]
+	}
+	return s
+}
+
+// plainText returns the text of the HTML fragment s with whitespace collapsed,
+// for the HTML site, where html/template escapes it again.
+func plainText(s string) string {
+	return strings.Join(strings.Fields(htmltext.Text(s)), " ")
+}
+
+// markdownText returns the text of the HTML fragment s escaped for Markdown.
+// Feed descriptions are often HTML, which would otherwise end up verbatim in the
+// list item.
+func markdownText(s string) string {
+	return escapeMarkdown(plainText(s))
+}
+
+var urlEscaper = strings.NewReplacer(
+	"(", `\(`, ")", `\)`, `\`, "%5C", " ", "%20", "\t", "%09", "\n", "", "\r", "",
+	"<", "%3C", ">", "%3E",
+)
+
+// markdownURL makes link safe as the target of a Markdown link: parentheses
+// are backslash-escaped so an unbalanced one does not end the target early,
+// and whitespace, angle brackets and backslashes are percent-encoded.
+func markdownURL(link string) string {
+	return urlEscaper.Replace(strings.TrimSpace(link))
+}
+
+// markdownCode returns s as an inline code span, with a fence longer than any
+// run of backticks in s.
+func markdownCode(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
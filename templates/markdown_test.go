@@ -0,0 +1,105 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestRender_MarkdownGolden renders titles, links and descriptions that break
+// naive Markdown. Run with -update after changing the template.
+func TestRender_MarkdownGolden(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "markdown.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c collector.Collector
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("cannot parse markdown.json: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := Render(context.Background(), &c, Config{UserName: "juev", BaseDir: dir}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "data", "2025-10.md"))
+	if err != nil {
+		t.Fatalf("week file not created: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "markdown.golden.md")
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("cannot read golden file: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("week file differs from %s:\n%s", golden, got)
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text, nothing to do", "plain text, nothing to do"},
+		{"# heading", `\# heading`},
+		{"12) twelve", `12\) twelve`},
+		{"a | b", `a \| b`},
+		{"x &copy; y & z", `x \&copy; y & z`},
+		{"  spread\n\tout  ", "spread out"},
+	}
+	for _, tt := range tests {
+		if got := escapeMarkdown(tt.in); got != tt.want {
+			t.Errorf("escapeMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Just text", "Just text"},
+		{"<p>One</p><p>Two <em>three</em>four</p>", "One Two threefour"},
+		{"<style>p{}</style>Fish &amp; chips", "Fish & chips"},
+		{"a < b", `a \< b`},
+	}
+	for _, tt := range tests {
+		if got := markdownText(tt.in); got != tt.want {
+			t.Errorf("markdownText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownURLAndCode(t *testing.T) {
+	if got, want := markdownURL("https://en.wikipedia.org/wiki/Go_(language)"), `https://en.wikipedia.org/wiki/Go_\(language\)`; got != want {
+		t.Errorf("markdownURL() = %q, want %q", got, want)
+	}
+	if got, want := markdownURL(" https://example.com/a b "), "https://example.com/a%20b"; got != want {
+		t.Errorf("markdownURL() = %q, want %q", got, want)
+	}
+
+	for in, want := range map[string]string{
+		"go":      "`go`",
+		"c`ode":   "``c`ode``",
+		"`tick":   "`` `tick ``",
+		"a\nb  c": "`a b c`",
+	} {
+		if got := markdownCode(in); got != want {
+			t.Errorf("markdownCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
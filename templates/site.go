@@ -0,0 +1,153 @@
+package templates
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+//go:embed site
+var siteFS embed.FS
+
+// sitePage is the data of an HTML page.
+type sitePage struct {
+	Title     string
+	SiteTitle string
+	UserName  string
+	// Root is the relative path from the page to the site root, "" or "../".
+	Root  string
+	Years []string
+	Count int
+	// Feeds is set when the feeds are published next to the pages.
+	Feeds bool
+	// Week is the week of a week page, or the latest week on the index.
+	Week *Week
+	// Weeks are the weeks of a year page.
+	Weeks []Week
+	// Archive lists all weeks by year on the index, newest first.
+	Archive []siteYear
+}
+
+type siteYear struct {
+	Year  string
+	Weeks []Week
+}
+
+// writeSite renders the static HTML site into cfg.HTMLDir: index.html with the
+// latest week and the archive, week/<name>.html per week, year/<year>.html per
+// ISO year, the stylesheet and, with cfg.Feeds, copies of the feeds. An empty .nojekyll file
+// makes GitHub Pages serve the files as they are.
+func writeSite(ctx context.Context, s *collector.Collector, weeks []Week, cfg Config) error {
+	dir := filepath.Join(cfg.BaseDir, cfg.HTMLDir)
+
+	pages, err := parseSite(cfg)
+	if err != nil {
+		return err
+	}
+
+	archive := yearsOf(weeks)
+	years := make([]string, len(archive))
+	for i, y := range archive {
+		years[i] = y.Year
+	}
+
+	base := sitePage{SiteTitle: cmp.Or(s.Title, "Links"), UserName: cfg.UserName, Years: years, Count: len(s.Items), Feeds: cfg.Feeds}
+
+	for _, week := range weeks {
+		page := base
+		page.Title, page.Root, page.Week = "Week "+week.Name, "../", &week
+		if err := writePage(ctx, pages["week"], page, filepath.Join(dir, "week", week.Name+".html")); err != nil {
+			return err
+		}
+	}
+
+	for _, year := range archive {
+		page := base
+		page.Title, page.Root, page.Weeks = year.Year, "../", slices.Clone(year.Weeks)
+		slices.Reverse(page.Weeks)
+		if err := writePage(ctx, pages["year"], page, filepath.Join(dir, "year", year.Year+".html")); err != nil {
+			return err
+		}
+	}
+
+	index := base
+	index.Title, index.Archive = base.SiteTitle, archive
+	if len(weeks) > 0 {
+		index.Week = &weeks[len(weeks)-1]
+	}
+	if err := writePage(ctx, pages["index"], index, filepath.Join(dir, "index.html")); err != nil {
+		return err
+	}
+
+	css, err := siteFS.ReadFile("site/style.css")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), css, 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".nojekyll"), nil, 0644); err != nil {
+		return err
+	}
+
+	if !cfg.Feeds {
+		return nil
+	}
+	feedCfg := cfg
+	feedCfg.BaseDir = dir
+	return writeFeeds(ctx, s, weeks, feedCfg)
+}
+
+// parseSite parses each page template together with the shared layout.
+func parseSite(cfg Config) (map[string]*template.Template, error) {
+	layout, err := template.New("layout.html").Funcs(template.FuncMap(Funcs(cfg))).ParseFS(siteFS, "site/layout.html")
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, name := range []string{"index", "week", "year"} {
+		t, err := template.Must(layout.Clone()).ParseFS(siteFS, "site/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		pages[name] = t
+	}
+	return pages, nil
+}
+
+func writePage(ctx context.Context, tmpl *template.Template, page sitePage, fileName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	if err := tmpl.ExecuteTemplate(&buf, "layout", page); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0770); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, []byte(buf.String()), 0644)
+}
+
+// yearsOf groups weeks by ISO year, newest year and week first.
+func yearsOf(weeks []Week) []siteYear {
+	var years []siteYear
+	for i := len(weeks) - 1; i >= 0; i-- {
+		year, _, _ := strings.Cut(weeks[i].Name, "-")
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, siteYear{Year: year})
+		}
+		years[len(years)-1].Weeks = append(years[len(years)-1].Weeks, weeks[i])
+	}
+	return years
+}
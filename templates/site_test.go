@@ -0,0 +1,84 @@
+package templates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	collector "github.com/juev/instapaper-collector"
+)
+
+func TestRender_HTMLSite(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &collector.Collector{
+		Title: "Links",
+		Items: []collector.Item{
+			{Title: "Last Year", Link: "https://example.com/2024", Published: "2024-12-10T10:00:00Z"},
+			{Title: "<script>alert(1)</script>", Link: "https://example.com/w9", Description: "<p>Tom &amp; <b>Jerry</b></p>", Published: "2025-02-24T10:00:00Z"},
+			{Title: "Bad Link", Link: "javascript:alert(1)", Published: "2025-03-03T10:00:00Z", Starred: true},
+		},
+	}
+
+	if err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir, HTMLDir: "docs", Feeds: true}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	for _, name := range []string{"index.html", "style.css", ".nojekyll", "links.atom", "weeks.atom", "week/2025-09.html", "week/2025-10.html", "year/2024.html", "year/2025.html"} {
+		if _, err := os.Stat(filepath.Join(dir, "docs", name)); err != nil {
+			t.Errorf("docs/%s not created: %v", name, err)
+		}
+	}
+
+	week, err := os.ReadFile(filepath.Join(dir, "docs", "week", "2025-09.html"))
+	if err != nil {
+		t.Fatalf("week page not created: %v", err)
+	}
+	page := string(week)
+	if strings.Contains(page, "<script>") || !strings.Contains(page, "&lt;script&gt;alert(1)&lt;/script&gt;") {
+		t.Error("week page should escape titles")
+	}
+	if !strings.Contains(page, "<p>Tom &amp; Jerry</p>") {
+		t.Error("week page should show the description as escaped text")
+	}
+	if !strings.Contains(page, `href="../style.css"`) || !strings.Contains(page, `href="../year/2025.html"`) {
+		t.Error("week page should link to the site root relatively")
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "docs", "index.html"))
+	if err != nil {
+		t.Fatalf("index.html not created: %v", err)
+	}
+	page = string(index)
+	if strings.Contains(page, "javascript:") {
+		t.Error("index should not contain javascript: links")
+	}
+	if !strings.Contains(page, `<li class="starred">`) {
+		t.Error("index should show the latest week with the starred item")
+	}
+	if strings.Index(page, `href="year/2025.html">2025`) > strings.Index(page, `href="year/2024.html">2024`) {
+		t.Error("index archive should list the newest year first")
+	}
+
+	year, err := os.ReadFile(filepath.Join(dir, "docs", "year", "2025.html"))
+	if err != nil {
+		t.Fatalf("year page not created: %v", err)
+	}
+	if strings.Index(string(year), "Week 2025-09") > strings.Index(string(year), "Week 2025-10") {
+		t.Error("year page should list weeks chronologically")
+	}
+}
+
+func TestRender_NoHTMLByDefault(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &collector.Collector{Items: []collector.Item{{Title: "One", Link: "https://example.com/one", Published: "2025-03-03T10:00:00Z"}}}
+	if err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); !os.IsNotExist(err) {
+		t.Error("index.html should only be written with HTMLDir set")
+	}
+}
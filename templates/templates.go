@@ -2,8 +2,11 @@ package templates
 
 import (
 	"cmp"
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
@@ -17,65 +20,182 @@ import (
 //go:embed template.tmpl
 var templateString string
 
+//go:embed index.tmpl
+var indexTemplateString string
+
+// Template file names looked up in a Config.Templates directory.
+const (
+	WeekTemplate   = "week.tmpl"
+	ReadmeTemplate = "readme.tmpl"
+	IndexTemplate  = "index.tmpl"
+)
+
 type Data struct {
 	Title    string
 	UserName string
 	Content  *collector.Collector
 	Count    int
+	// Weeks lists all weeks, newest first. It is only set for the index.
+	Weeks []Week
+}
+
+// pageTemplates are the templates of the Markdown files.
+type pageTemplates struct {
+	week, readme, index *template.Template
+}
+
+// GroupBy selects the item timestamp used to bucket items into weeks.
+type GroupBy int
+
+const (
+	// GroupByPublished buckets items by Item.Published, the feed date.
+	GroupByPublished GroupBy = iota
+	// GroupByCollected buckets items by Item.Collected, the time the collector
+	// first saw them, falling back to Published for items collected before it was recorded.
+	GroupByCollected
+)
+
+// Config controls how the collection is rendered.
+type Config struct {
+	UserName string
+	// WeekOffset shifts the ISO week boundary BACK from Monday 00:00 by the given hours
+	// (e.g. 47 = Saturday 01:00, 0 = standard Monday).
+	WeekOffset int
+	BaseDir    string
+	GroupBy    GroupBy
+	// SiteURL is where README.md and data/*.md are published, e.g.
+	// "https://github.com/juev/links/blob/main". The weekly feed links to the
+	// week files under it; without it, feed entries have no week links.
+	SiteURL string
+	// Archive writes ARCHIVE.md, an index of all weeks.
+	Archive bool
+	// Feeds writes Atom feeds of the latest links and of the weekly digests.
+	Feeds bool
+	// RSS, with Feeds, also writes RSS 2.0 versions of the Atom feeds.
+	RSS bool
+	// HTMLDir, relative to BaseDir, enables the static HTML site, written
+	// next to the Markdown files (e.g. "docs" for GitHub Pages).
+	HTMLDir string
+	// Templates overrides the embedded Markdown templates. It is either a
+	// directory with any of WeekTemplate, ReadmeTemplate and IndexTemplate,
+	// or a single template file used for both the week pages and README.md.
+	Templates string
+	// Location is the time zone of the date and groupBy template functions.
+	// It defaults to UTC.
+	Location *time.Location
+}
+
+// Week is one ISO week of items, named like "2025-09".
+type Week struct {
+	Name  string
+	Items []collector.Item
 }
 
 // TemplateFile generates weekly markdown files and README.md.
 // weekOffset shifts the ISO week boundary BACK from Monday 00:00 by the given hours
 // (e.g. 47 = Saturday 01:00, 0 = standard Monday).
 func TemplateFile(s *collector.Collector, userName string, weekOffset int, baseDir string) error {
-	tmpl, err := template.New("links").Parse(templateString)
+	return TemplateFileContext(context.Background(), s, userName, weekOffset, baseDir)
+}
+
+// TemplateFileContext is like TemplateFile but stops writing files once ctx is done.
+func TemplateFileContext(ctx context.Context, s *collector.Collector, userName string, weekOffset int, baseDir string) error {
+	return Render(ctx, s, Config{UserName: userName, WeekOffset: weekOffset, BaseDir: baseDir})
+}
+
+// Render generates weekly markdown files, README.md and, as enabled by cfg,
+// the ARCHIVE.md index, the feeds (links.atom, weeks.atom and, with cfg.RSS,
+// links.rss and weeks.rss) and the HTML site in cfg.HTMLDir.
+// It stops writing files once ctx is done.
+func Render(ctx context.Context, s *collector.Collector, cfg Config) error {
+	tmpls, err := loadTemplates(cfg)
+	if err != nil {
+		return err
+	}
+
+	weeks, err := Weeks(s.Items, cfg)
 	if err != nil {
 		return err
 	}
 
-	items := slices.Clone(s.Items)
-	slices.SortFunc(items, func(a, b collector.Item) int {
-		return cmp.Compare(a.Published, b.Published)
+	r := Data{UserName: cfg.UserName}
+	for _, week := range weeks {
+		weekItems := &collector.Collector{Title: s.Title, Items: week.Items}
+		if err := writeTemplate(ctx, &r, week.Name, weekItems, tmpls.week, cfg.BaseDir); err != nil {
+			return err
+		}
+	}
+
+	r.Count = len(s.Items)
+	latestWeek := &collector.Collector{Title: s.Title}
+	if len(weeks) > 0 {
+		latestWeek.Items = weeks[len(weeks)-1].Items
+	}
+	if err := writeTemplate(ctx, &r, "", latestWeek, tmpls.readme, cfg.BaseDir); err != nil {
+		return err
+	}
+
+	if cfg.Archive {
+		index := Data{Title: cmp.Or(s.Title, "Links"), UserName: cfg.UserName, Content: s, Count: len(s.Items), Weeks: slices.Clone(weeks)}
+		slices.Reverse(index.Weeks)
+		if err := writeFile(ctx, tmpls.index, index, filepath.Join(cfg.BaseDir, "ARCHIVE.md")); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Feeds {
+		if err := writeFeeds(ctx, s, weeks, cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.HTMLDir != "" {
+		return writeSite(ctx, s, weeks, cfg)
+	}
+	return nil
+}
+
+// Weeks buckets items into ISO weeks as configured by cfg,
+// in chronological order with items sorted within each week.
+func Weeks(items []collector.Item, cfg Config) ([]Week, error) {
+	items = slices.Clone(items)
+	slices.SortStableFunc(items, func(a, b collector.Item) int {
+		return cmp.Compare(itemTime(a, cfg.GroupBy), itemTime(b, cfg.GroupBy))
 	})
 
-	var weekNumber, currentWeek string
-	r := Data{UserName: userName}
-	weekItems := &collector.Collector{Title: s.Title}
-	offset := time.Duration(weekOffset) * time.Hour
+	var weeks []Week
+	offset := time.Duration(cfg.WeekOffset) * time.Hour
 
 	for _, item := range items {
-		t, err := time.Parse(time.RFC3339, item.Published)
+		t, err := time.Parse(time.RFC3339, itemTime(item, cfg.GroupBy))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		year, week := t.Add(offset).ISOWeek()
-		currentWeek = fmt.Sprintf("%d-%02d", year, week)
-
-		if weekNumber != currentWeek {
-			if weekNumber != "" {
-				if err := writeTemplate(&r, weekNumber, weekItems, tmpl, baseDir); err != nil {
-					return err
-				}
-			}
-			weekNumber = currentWeek
-			weekItems.Items = nil
-		}
-		weekItems.Items = append(weekItems.Items, item)
-	}
+		name := fmt.Sprintf("%d-%02d", year, week)
 
-	if weekNumber != "" {
-		if err := writeTemplate(&r, weekNumber, weekItems, tmpl, baseDir); err != nil {
-			return err
+		if len(weeks) == 0 || weeks[len(weeks)-1].Name != name {
+			weeks = append(weeks, Week{Name: name})
 		}
+		weeks[len(weeks)-1].Items = append(weeks[len(weeks)-1].Items, item)
 	}
 
-	r.Count = len(items)
-	latestWeek := &collector.Collector{Title: s.Title, Items: weekItems.Items}
-	return writeTemplate(&r, "", latestWeek, tmpl, baseDir)
+	return weeks, nil
+}
+
+func itemTime(item collector.Item, groupBy GroupBy) string {
+	if groupBy == GroupByCollected && item.Collected != "" {
+		return item.Collected
+	}
+	return item.Published
 }
 
-func writeTemplate(r *Data, weekNumber string, weekItems *collector.Collector, tmpl *template.Template, baseDir string) error {
+func writeTemplate(ctx context.Context, r *Data, weekNumber string, weekItems *collector.Collector, tmpl *template.Template, baseDir string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.Title = weekNumber
 	r.Content = weekItems
 	fileName := filepath.Join(baseDir, "data", weekNumber+".md")
@@ -85,14 +205,88 @@ func writeTemplate(r *Data, weekNumber string, weekItems *collector.Collector, t
 		fileName = filepath.Join(baseDir, "README.md")
 	}
 
-	var buf strings.Builder
-	if err := tmpl.Execute(&buf, r); err != nil {
+	return writeFile(ctx, tmpl, r, fileName)
+}
+
+// writeFile executes tmpl with data into fileName. Execution errors name
+// the template file and line.
+func writeFile(ctx context.Context, tmpl *template.Template, data any, fileName string) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("cannot render %s: %w", fileName, err)
+	}
+
 	if err := os.MkdirAll(filepath.Dir(fileName), 0770); err != nil {
 		return err
 	}
 
 	return os.WriteFile(fileName, []byte(buf.String()), 0644)
 }
+
+// loadTemplates parses the embedded templates and the overrides found at
+// cfg.Templates, all with the functions of Funcs. Templates read from disk are
+// named by their path, so parse and execution errors point at the file and line.
+func loadTemplates(cfg Config) (*pageTemplates, error) {
+	funcs := template.FuncMap(Funcs(cfg))
+
+	page, err := template.New("template.tmpl").Funcs(funcs).Parse(templateString)
+	if err != nil {
+		return nil, err
+	}
+	index, err := template.New("index.tmpl").Funcs(funcs).Parse(indexTemplateString)
+	if err != nil {
+		return nil, err
+	}
+	tmpls := &pageTemplates{week: page, readme: page, index: index}
+	path := cfg.Templates
+	if path == "" {
+		return tmpls, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read templates: %w", err)
+	}
+	if !info.IsDir() {
+		t, err := parseTemplateFile(path, funcs)
+		if err != nil {
+			return nil, err
+		}
+		tmpls.week, tmpls.readme = t, t
+		return tmpls, nil
+	}
+
+	for name, dst := range map[string]**template.Template{
+		WeekTemplate:   &tmpls.week,
+		ReadmeTemplate: &tmpls.readme,
+		IndexTemplate:  &tmpls.index,
+	} {
+		fileName := filepath.Join(path, name)
+		if _, err := os.Stat(fileName); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		t, err := parseTemplateFile(fileName, funcs)
+		if err != nil {
+			return nil, err
+		}
+		*dst = t
+	}
+
+	return tmpls, nil
+}
+
+func parseTemplateFile(fileName string, funcs template.FuncMap) (*template.Template, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read template: %w", err)
+	}
+	t, err := template.New(fileName).Funcs(funcs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template: %w", err)
+	}
+	return t, nil
+}
@@ -1,10 +1,16 @@
 package templates
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"text/template"
+	"time"
 
 	collector "github.com/juev/instapaper-collector"
 )
@@ -136,3 +142,349 @@ func TestTemplateFile_WeekOffsetZero(t *testing.T) {
 		t.Errorf("expected 1 weekly file, got %d", len(entries))
 	}
 }
+
+func TestTemplateFileContext_Canceled(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &collector.Collector{
+		Title: "Test",
+		Items: []collector.Item{
+			{Title: "Article", Link: "https://example.com/a", Published: "2025-02-24T10:00:00Z"},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := TemplateFileContext(ctx, c, "juev", 47, dir); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); err == nil {
+		t.Error("README.md should not be written when canceled")
+	}
+}
+
+func TestRender_GroupByCollected(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &collector.Collector{
+		Title: "Test",
+		Items: []collector.Item{
+			{Title: "Old Article", Link: "https://example.com/old", Published: "2019-06-01T10:00:00Z", Collected: "2025-03-03T10:00:00Z"},
+			{Title: "Legacy Item", Link: "https://example.com/legacy", Published: "2025-03-04T10:00:00Z"},
+		},
+	}
+
+	err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir, GroupBy: GroupByCollected})
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "data"))
+	if err != nil {
+		t.Fatalf("data/ dir not created: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "2025-10.md" {
+		t.Errorf("expected only data/2025-10.md, got %v", entries)
+	}
+}
+
+func TestWeeks_GroupByPublished(t *testing.T) {
+	items := []collector.Item{
+		{Link: "https://example.com/b", Published: "2025-03-03T10:00:00Z", Collected: "2025-03-03T10:00:00Z"},
+		{Link: "https://example.com/a", Published: "2019-06-01T10:00:00Z", Collected: "2025-03-03T11:00:00Z"},
+	}
+
+	weeks, err := Weeks(items, Config{})
+	if err != nil {
+		t.Fatalf("Weeks() error: %v", err)
+	}
+	if len(weeks) != 2 || weeks[0].Name != "2019-22" || weeks[1].Name != "2025-10" {
+		t.Errorf("unexpected weeks: %+v", weeks)
+	}
+}
+
+func TestRender_ItemMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &collector.Collector{
+		Title: "Test",
+		Items: []collector.Item{
+			{
+				Title:      "Episode",
+				Link:       "https://example.com/episode",
+				Published:  "2025-03-03T10:00:00Z",
+				Author:     "Jane Doe",
+				Categories: []string{"go", "podcasts"},
+				Enclosures: []collector.Enclosure{{URL: "https://example.com/episode.mp3", Type: "audio/mpeg"}},
+				Comments:   "https://example.com/episode#comments",
+			},
+			{
+				Title:      "Starred",
+				Link:       "https://example.com/starred",
+				Published:  "2025-03-03T11:00:00Z",
+				Starred:    true,
+				Highlights: []collector.Highlight{{Text: "First quote"}, {Text: "Second quote"}},
+			},
+		},
+	}
+
+	if err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatalf("README.md not created: %v", err)
+	}
+
+	for _, want := range []string{
+		"- [Episode](https://example.com/episode) by Jane Doe [audio](https://example.com/episode.mp3) ([comments](https://example.com/episode#comments)) `go` `podcasts`\n",
+		"- ★ [Starred](https://example.com/starred)\n  > First quote\n  > Second quote\n",
+	} {
+		if !strings.Contains(string(readme), want) {
+			t.Errorf("README.md should contain %q, got:\n%s", want, readme)
+		}
+	}
+}
+
+func TestRender_TemplateOverrides(t *testing.T) {
+	dir := t.TempDir()
+	tmplDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmplDir, WeekTemplate), []byte("Week {{ .Title }}: {{ len .Content.Items }} links\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmplDir, IndexTemplate), []byte("{{ range .Weeks }}{{ .Name }} {{ end }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector.Collector{
+		Title: "Links",
+		Items: []collector.Item{
+			{Title: "Week 9 Article", Link: "https://example.com/w9", Published: "2025-02-24T10:00:00Z"},
+			{Title: "Week 10 Article", Link: "https://example.com/w10", Published: "2025-03-03T10:00:00Z"},
+		},
+	}
+
+	if err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir, Templates: tmplDir, Archive: true}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	week, err := os.ReadFile(filepath.Join(dir, "data", "2025-10.md"))
+	if err != nil {
+		t.Fatalf("week file not created: %v", err)
+	}
+	if string(week) != "Week 2025-10: 1 links\n" {
+		t.Errorf("week file should use the override, got %q", week)
+	}
+
+	readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatalf("README.md not created: %v", err)
+	}
+	if !strings.Contains(string(readme), "## License") {
+		t.Error("README.md should fall back to the embedded template")
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "ARCHIVE.md"))
+	if err != nil {
+		t.Fatalf("ARCHIVE.md not created: %v", err)
+	}
+	if string(index) != "2025-10 2025-09 \n" {
+		t.Errorf("index should list weeks newest first, got %q", index)
+	}
+}
+
+func TestRender_NoArchiveByDefault(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &collector.Collector{Items: []collector.Item{{Title: "One", Link: "https://example.com/one", Published: "2025-03-03T10:00:00Z"}}}
+	if err := TemplateFile(c, "juev", 0, dir); err != nil {
+		t.Fatalf("TemplateFile() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ARCHIVE.md")); !os.IsNotExist(err) {
+		t.Error("ARCHIVE.md should only be written with Archive enabled")
+	}
+}
+
+func TestRender_TemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(t.TempDir(), "links.tmpl")
+	if err := os.WriteFile(file, []byte("{{ range .Content.Items }}* {{ .Link }}\n{{ end }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector.Collector{Items: []collector.Item{{Title: "One", Link: "https://example.com/one", Published: "2025-03-03T10:00:00Z"}}}
+	if err := Render(context.Background(), c, Config{UserName: "juev", BaseDir: dir, Templates: file}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	for _, name := range []string{"README.md", filepath.Join("data", "2025-10.md")} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s not created: %v", name, err)
+		}
+		if string(got) != "* https://example.com/one\n" {
+			t.Errorf("%s should use the template file, got %q", name, got)
+		}
+	}
+}
+
+func TestRender_TemplateErrors(t *testing.T) {
+	c := &collector.Collector{Items: []collector.Item{{Title: "One", Link: "https://example.com/one", Published: "2025-03-03T10:00:00Z"}}}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"parse", "line one\n{{ .Title\n", "readme.tmpl:2"},
+		{"execute", "line one\nline two\n{{ .Missing }}\n", "readme.tmpl:3:3"},
+	}
+
+	for _, tt := range tests {
+		tmplDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmplDir, ReadmeTemplate), []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		err := Render(context.Background(), c, Config{BaseDir: t.TempDir(), Templates: tmplDir})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Render() error = %v, want it to name %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+	now := func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC) }
+	fm := funcs(Config{Location: berlin}, now)
+
+	item := collector.Item{
+		Title:       "A *bold* [claim]",
+		Link:        "https://www.Example.com/post?id=1",
+		Description: "Short.",
+		Content:     "<p>" + strings.Repeat("word ", 450) + "</p>",
+		Published:   "2025-03-09T23:30:00Z",
+		Categories:  []string{"go", "web"},
+	}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{ domain .Link }}`, "example.com"},
+		{`{{ domain "not a url" }}`, ""},
+		{`{{ .Published | date "2006-01-02 15:04 MST" }}`, "2025-03-10 00:30 CET"},
+		{`{{ date "2006" "" }}`, ""},
+		{`{{ relative .Published }}`, "12 hours ago"},
+		{`{{ relative "2025-03-10T11:59:30Z" }}`, "just now"},
+		{`{{ relative "2025-02-24T12:00:00Z" }}`, "2 weeks ago"},
+		{`{{ relative "2024-03-10T12:00:00Z" }}`, "1 year ago"},
+		{`{{ relative "2025-03-10T14:00:00Z" }}`, "in 2 hours"},
+		{`{{ truncate 14 "The quick brown fox" }}`, "The quick…"},
+		{`{{ truncate 40 "The quick brown fox" }}`, "The quick brown fox"},
+		{`{{ escapeMarkdown .Title }}`, `A \*bold\* \[claim\]`},
+		{`{{ escapeMarkdown "a_b\nc|d" }}`, `a\_b c\|d`},
+		{`{{ readingTime . }}`, "3"},
+		{`{{ readingTime "" }}`, "1"},
+		{`{{ join ", " .Categories }}`, "go, web"},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := template.New("t").Funcs(fm).Parse(tt.tmpl)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.tmpl, err)
+		}
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, item); err != nil {
+			t.Fatalf("Execute(%q) error: %v", tt.tmpl, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s = %q, want %q", tt.tmpl, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestFuncs_GroupAndSort(t *testing.T) {
+	items := []collector.Item{
+		{Title: "b", Link: "https://b.example/1", Published: "2025-03-04T10:00:00Z", Categories: []string{"go"}},
+		{Title: "A", Link: "https://a.example/1", Published: "2025-03-09T10:00:00Z", Categories: []string{"web", "go"}},
+		{Title: "c", Link: "https://b.example/2", Published: "2025-03-03T10:00:00Z"},
+	}
+	fm := funcs(Config{}, time.Now)
+
+	render := func(tmpl string) string {
+		t.Helper()
+		tp, err := template.New("t").Funcs(fm).Parse(tmpl)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tmpl, err)
+		}
+		var buf strings.Builder
+		if err := tp.Execute(&buf, items); err != nil {
+			t.Fatalf("Execute(%q) error: %v", tmpl, err)
+		}
+		return buf.String()
+	}
+
+	groups := `{{ range groupBy %q . }}{{ .Key }}:{{ range .Items }}{{ .Title }}{{ end }} {{ end }}`
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{fmt.Sprintf(groups, "domain"), "a.example:A b.example:bc "},
+		{fmt.Sprintf(groups, "tag"), ":c go:bA web:A "},
+		{fmt.Sprintf(groups, "weekday"), "Monday:c Tuesday:b Sunday:A "},
+		{`{{ range sortBy "published" . }}{{ .Title }}{{ end }}`, "cbA"},
+		{`{{ range sortBy "-published" . }}{{ .Title }}{{ end }}`, "Abc"},
+		{`{{ range sortBy "title" . }}{{ .Title }}{{ end }}`, "Abc"},
+		{`{{ range sortBy "domain" . }}{{ .Title }}{{ end }}`, "Abc"},
+	}
+	for _, tt := range tests {
+		if got := render(tt.tmpl); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	if got := render(`{{ range sortBy "title" . }}{{ end }}{{ range . }}{{ .Title }}{{ end }}`); got != "bAc" {
+		t.Errorf("sortBy should not reorder its argument, got %q", got)
+	}
+
+	for _, tmpl := range []string{`{{ groupBy "color" . }}`, `{{ sortBy "color" . }}`} {
+		tp := template.Must(template.New("t").Funcs(fm).Parse(tmpl))
+		if err := tp.Execute(io.Discard, items); err == nil {
+			t.Errorf("%s should fail", tmpl)
+		}
+	}
+}
+
+func TestRender_TemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+	tmplDir := t.TempDir()
+	week := `{{ range groupBy "domain" .Content.Items }}## {{ .Key }}
+{{ range .Items }}- {{ escapeMarkdown .Title }} ({{ .Published | date "Jan 2" }})
+{{ end }}{{ end }}`
+	if err := os.WriteFile(filepath.Join(tmplDir, WeekTemplate), []byte(week), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector.Collector{Items: []collector.Item{
+		{Title: "one_two", Link: "https://www.example.com/one", Published: "2025-03-03T10:00:00Z"},
+	}}
+	if err := Render(context.Background(), c, Config{BaseDir: dir, Templates: tmplDir}); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "data", "2025-10.md"))
+	if err != nil {
+		t.Fatalf("week file not created: %v", err)
+	}
+	if want := "## example.com\n- one\\_two (Mar 3)\n"; string(got) != want {
+		t.Errorf("week file = %q, want %q", got, want)
+	}
+}